@@ -230,3 +230,28 @@ func (abi *ABI) UnpackInputWithoutMethod(v interface{}, data []byte) (err error)
 	}
 	return nil
 }
+
+// VMType return "EVM", the vm type of contracts described by abi
+func (abi ABI) VMType() string {
+	return "EVM"
+}
+
+// EncodeInput return the payload invoking method with args, it is the same as Pack
+func (abi ABI) EncodeInput(method string, args ...interface{}) ([]byte, error) {
+	return abi.Pack(method, args...)
+}
+
+// DecodeOutput decode the return value of method into a list of Go values in the order of outputs
+func (abi ABI) DecodeOutput(method string, ret []byte) ([]interface{}, error) {
+	m, err := abi.GetMethod(method)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Outputs) == 0 {
+		return []interface{}{}, nil
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("abi: unmarshalling empty output")
+	}
+	return m.Outputs.UnpackValues(ret)
+}
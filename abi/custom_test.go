@@ -35,3 +35,28 @@ func TestABI_UnpackInput(t *testing.T) {
 	assert.Equal(t, uint32(1), r1)
 	assert.Equal(t, uint32(2), r2)
 }
+
+func TestABI_DecodeOutput(t *testing.T) {
+	ABI, _ := JSON(strings.NewReader(abi))
+	assert.Equal(t, "EVM", ABI.VMType())
+
+	input, err := ABI.EncodeInput("add", uint32(1), uint32(2))
+	assert.Nil(t, err)
+	packed, _ := ABI.Pack("add", uint32(1), uint32(2))
+	assert.Equal(t, packed, input)
+
+	ret := make([]byte, 32)
+	ret[31] = 3
+	values, err := ABI.DecodeOutput("getSum", ret)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{uint32(3)}, values)
+
+	values, err = ABI.DecodeOutput("increment", nil)
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+
+	_, err = ABI.DecodeOutput("getSum", nil)
+	assert.NotNil(t, err)
+	_, err = ABI.DecodeOutput("notExist", ret)
+	assert.NotNil(t, err)
+}
@@ -23,28 +23,36 @@ import (
 )
 
 const (
-	ECKDF2 = "0x01"
-	ECDES  = "0x02"
-	ECRAW  = "0x03"
-	ECAES  = "0x04"
-	EC3DES = "0x05"
-
-	SMSM4  = "0x11"
-	SMDES  = "0x12"
-	SMRAW  = "0x13"
-	SMAES  = "0x14"
-	SM3DES = "0x15"
-
-	ED25519DES  = "0x21"
-	ED25519RAW  = "0x22"
-	ED25519AES  = "0x23"
-	ED255193DES = "0x24"
-
-	ECKDF2R1 = "0x011"
-	ECDESR1  = "0x021"
-	ECRAWR1  = "0x031"
-	ECAESR1  = "0x041"
-	EC3DESR1 = "0x051"
+	// ECKDF2 and ECSCRYPT encrypt the private key as Web3 Secret Storage v3 with PBKDF2 and scrypt,
+	// so do the other KDF2 and SCRYPT types
+	ECKDF2   = "0x01"
+	ECDES    = "0x02"
+	ECRAW    = "0x03"
+	ECAES    = "0x04"
+	EC3DES   = "0x05"
+	ECSCRYPT = "0x06"
+
+	SMSM4    = "0x11"
+	SMDES    = "0x12"
+	SMRAW    = "0x13"
+	SMAES    = "0x14"
+	SM3DES   = "0x15"
+	SMKDF2   = "0x16"
+	SMSCRYPT = "0x17"
+
+	ED25519DES    = "0x21"
+	ED25519RAW    = "0x22"
+	ED25519AES    = "0x23"
+	ED255193DES   = "0x24"
+	ED25519KDF2   = "0x25"
+	ED25519SCRYPT = "0x26"
+
+	ECKDF2R1   = "0x011"
+	ECDESR1    = "0x021"
+	ECRAWR1    = "0x031"
+	ECAESR1    = "0x041"
+	EC3DESR1   = "0x051"
+	ECSCRYPTR1 = "0x061"
 
 	PKI = "0x41"
 
@@ -71,6 +79,8 @@ type accountJSON struct {
 	PublicKey   string `json:"publicKey,omitempty"`
 	PrivateKey  string `json:"privateKey,omitempty"`
 	EncodedCert string `json:"encodedCert,omitempty"` // Marshalled certificate by MarshalCertificate()
+	// Crypto is the encrypted private key of the KDF2 and SCRYPT types, PrivateKey is empty in that case
+	Crypto *keystoreCrypto `json:"crypto,omitempty"`
 }
 
 func ZeroPadding(ciphertext []byte, blockSize int) []byte {
@@ -158,137 +168,53 @@ func DesDecrypt(crypted, key []byte) ([]byte, error) {
 
 // NewAccountJson generate account json by account type
 func NewAccountJson(acType, password string) (string, error) {
-	accountJson := new(accountJSON)
-	var privateKey []byte
-
+	var key Key
 	if strings.HasPrefix(acType, "0x0") {
-		key, err := asym.GenerateKey(asym.AlgoP256K1)
+		ecKey, err := asym.GenerateKey(asym.AlgoP256K1)
 		if len(acType) == 5 && acType[4] == '1' {
-			key, err = asym.GenerateKey(asym.AlgoP256R1)
+			ecKey, err = asym.GenerateKey(asym.AlgoP256R1)
 		}
 		if err != nil {
 			return "", err
 		}
-		switch acType {
-		case ECKDF2, ECKDF2R1:
-			return "", errors.New("not support KDF2 now")
-		case ECDES, ECDESR1:
-			accountJson.Algo = ECDES
-			privateKey, err = DesEncrypt(math.PaddedBigBytes(key.D, 32), []byte(password))
-			if err != nil {
-				return "", err
-			}
-		case ECRAW, ECRAWR1:
-			accountJson.Algo = ECRAW
-			privateKey = math.PaddedBigBytes(key.D, 32)
-		case ECAES, ECAESR1:
-			accountJson.Algo = ECAES
-			aes := new(inter.AES)
-			reader := bytes.NewReader(AtPadding([]byte(password), 32)[:16])
-			privateKey, err = aes.Encrypt(AtPadding([]byte(password), 32), math.PaddedBigBytes(key.D, 32), reader)
-			if err != nil {
-				return "", err
-			}
-			privateKey = privateKey[16:]
-		case EC3DES, EC3DESR1:
-			accountJson.Algo = EC3DES
-			privateKey, err = inter.TripleDesEncrypt8(math.PaddedBigBytes(key.D, 32), AtPadding([]byte(password), 24))
-			if err != nil {
-				return "", err
-			}
-		default:
-			return "", errors.New("not support crypt type " + acType)
-		}
-		accountJson.Version = V4
-		//if acType == ECDES {
-		//	accountJson.Version = V1
-		//}
-		accountJson.Address, accountJson.PublicKey = getAddressAndPublic(&ECDSAKey{key})
-		accountJson.PrivateKey = common.Bytes2Hex(privateKey)
+		key = &ECDSAKey{ecKey}
 	} else if strings.HasPrefix(acType, "0x1") {
-		key, err := gm.GenerateSM2Key()
+		smKey, err := gm.GenerateSM2Key()
 		if err != nil {
 			return "", err
 		}
-		tempKey := common.LeftPadBytes(key.K[:], 32)
-		var privateKey []byte
-		switch acType {
-		case SMSM4:
-			accountJson.Algo = SMSM4
-			privateKey, err = gm.Sm4EncryptCBC(AtPadding([]byte(password), 16), tempKey, rand.Reader)
-			if err != nil {
-				return "", err
-			}
-		case SMDES:
-			accountJson.Algo = SMDES
-			privateKey, err = DesEncrypt(tempKey, []byte(password))
-			if err != nil {
-				return "", err
-			}
-		case SMRAW:
-			accountJson.Algo = SMRAW
-			privateKey = tempKey
-		case SMAES:
-			accountJson.Algo = SMAES
-			aes := new(inter.AES)
-			reader := bytes.NewReader(AtPadding([]byte(password), 32)[:16])
-			privateKey, err = aes.Encrypt(AtPadding([]byte(password), 32), tempKey, reader)
-			if err != nil {
-				return "", err
-			}
-			privateKey = privateKey[16:]
-		case SM3DES:
-			accountJson.Algo = SM3DES
-			privateKey, err = inter.TripleDesEncrypt8(tempKey, AtPadding([]byte(password), 24))
-			if err != nil {
-				return "", err
-			}
-		default:
-			return "", errors.New("not support crypt type " + acType)
-		}
-		accountJson.Version = V4
-		accountJson.PrivateKey = common.Bytes2Hex(privateKey)
-		accountJson.Address, accountJson.PublicKey = getAddressAndPublic(&SM2Key{key})
+		key = &SM2Key{smKey}
 	} else if strings.HasPrefix(acType, "0x2") {
-		var err error
 		vk, pk := ed25519.GenerateKey(rand.Reader)
 		if vk == nil || pk == nil {
 			return "", errors.New("generate ed25519 key failed")
 		}
-		tempKey := vk[:]
-		switch acType {
-		case ED25519DES:
-			accountJson.Algo = ED25519DES
-			privateKey, err = DesEncrypt(tempKey, []byte(password))
-			if err != nil {
-				return "", err
-			}
-		case ED25519RAW:
-			accountJson.Algo = ED25519RAW
-			privateKey = tempKey
-		case ED25519AES:
-			accountJson.Algo = ED25519AES
-			aes := new(inter.AES)
-			reader := bytes.NewReader(AtPadding([]byte(password), 32)[:16])
-			privateKey, err = aes.Encrypt(AtPadding([]byte(password), 32), tempKey, reader)
-			if err != nil {
-				return "", err
-			}
-			privateKey = privateKey[16:]
-		case ED255193DES:
-			accountJson.Algo = ED255193DES
-			privateKey, err = inter.TripleDesEncrypt8(tempKey, AtPadding([]byte(password), 24))
-			if err != nil {
-				return "", err
-			}
-		default:
-			return "", errors.New("not support crypt type " + acType)
-		}
-		accountJson.Version = V4
-		accountJson.Address, accountJson.PublicKey = getAddressAndPublic(&ED25519Key{vk})
-		accountJson.PrivateKey = common.Bytes2Hex(privateKey)
+		key = &ED25519Key{vk}
+	} else {
+		return "", errors.New("not support crypt type " + acType)
+	}
+	accountJson, err := encryptAccountKey(key, acType, password)
+	if err != nil {
+		return "", err
+	}
+	// the ECDSA accounts of secp256r1 encrypted without kdf are written with the algo of secp256k1
+	if len(acType) == 5 && accountJson.Crypto == nil {
+		accountJson.Algo = acType[:4]
+	}
+	jsonBytes, err := json.Marshal(accountJson)
+	if err != nil {
+		return "", err
 	}
+	return string(jsonBytes), nil
+}
 
+// newAccountJsonFromKey encrypt the private key of key by password as acType to account json, which is
+// written with acType so that the key is decrypted as the same algorithm
+func newAccountJsonFromKey(key Key, acType, password string) (string, error) {
+	accountJson, err := encryptAccountKey(key, acType, password)
+	if err != nil {
+		return "", err
+	}
 	jsonBytes, err := json.Marshal(accountJson)
 	if err != nil {
 		return "", err
@@ -296,6 +222,85 @@ func NewAccountJson(acType, password string) (string, error) {
 	return string(jsonBytes), nil
 }
 
+// encryptAccountKey encrypt the private key of key by password as acType
+func encryptAccountKey(key Key, acType, password string) (*accountJSON, error) {
+	tempKey, err := rawPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !matchAcType(key, acType) {
+		return nil, fmt.Errorf("crypt type %s does not match %s key", acType, key.Algorithm())
+	}
+
+	accountJson := new(accountJSON)
+	var privateKey []byte
+	accountJson.Algo = acType
+	switch acType {
+	case ECKDF2, ECKDF2R1, ECSCRYPT, ECSCRYPTR1, SMKDF2, SMSCRYPT, ED25519KDF2, ED25519SCRYPT:
+		accountJson.Crypto, err = encryptKeystore(tempKey, password, acType)
+	case ECDES, ECDESR1, SMDES, ED25519DES:
+		privateKey, err = DesEncrypt(tempKey, []byte(password))
+	case ECRAW, ECRAWR1, SMRAW, ED25519RAW:
+		privateKey = tempKey
+	case ECAES, ECAESR1, SMAES, ED25519AES:
+		aes := new(inter.AES)
+		reader := bytes.NewReader(AtPadding([]byte(password), 32)[:16])
+		privateKey, err = aes.Encrypt(AtPadding([]byte(password), 32), tempKey, reader)
+		if err == nil {
+			privateKey = privateKey[16:]
+		}
+	case EC3DES, EC3DESR1, SM3DES, ED255193DES:
+		privateKey, err = inter.TripleDesEncrypt8(tempKey, AtPadding([]byte(password), 24))
+	case SMSM4:
+		privateKey, err = gm.Sm4EncryptCBC(AtPadding([]byte(password), 16), tempKey, rand.Reader)
+	default:
+		return nil, errors.New("not support crypt type " + acType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	accountJson.Version = V4
+	accountJson.Address, accountJson.PublicKey = getAddressAndPublic(key)
+	accountJson.PrivateKey = common.Bytes2Hex(privateKey)
+	return accountJson, nil
+}
+
+// rawPrivateKey return the private key of key encrypted in account json
+func rawPrivateKey(key Key) ([]byte, error) {
+	switch k := key.(type) {
+	case *ECDSAKey:
+		return math.PaddedBigBytes(k.D, 32), nil
+	case *SM2Key:
+		return common.LeftPadBytes(k.K[:], 32), nil
+	case *ED25519Key:
+		return k.EDDSAPrivateKey[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// matchAcType return whether acType is an account type of the algorithm of key
+func matchAcType(key Key, acType string) bool {
+	algorithm := acTypeAlgorithm(acType)
+	return algorithm != "" && algorithm == key.Algorithm()
+}
+
+// acTypeAlgorithm return the algorithm of the keys of acType, or empty if acType is unknown
+func acTypeAlgorithm(acType string) Algorithm {
+	switch {
+	case strings.HasPrefix(acType, "0x0") && len(acType) == 4:
+		return AlgorithmECDSAK1
+	case strings.HasPrefix(acType, "0x0") && len(acType) == 5:
+		return AlgorithmECDSAR1
+	case strings.HasPrefix(acType, "0x1"):
+		return AlgorithmSM2
+	case strings.HasPrefix(acType, "0x2"):
+		return AlgorithmED25519
+	default:
+		return ""
+	}
+}
+
 // NewAccountJsonFromPfx create account json using pfx cert
 func NewAccountJsonFromPfx(password string, pfx []byte) (string, error) {
 	accountJson := new(accountJSON)
@@ -322,26 +327,52 @@ func NewAccountJsonFromPfx(password string, pfx []byte) (string, error) {
 }
 
 // GenKeyFromAccountJson generate ecdsa.Key or gm.Key by account type
-func GenKeyFromAccountJson(accountJson, password string) (key interface{}, err error) {
+func GenKeyFromAccountJson(accountJson, password string) (key Key, err error) {
+	_, key, err = parseAccountKey(accountJson, password)
+	return key, err
+}
+
+// parseAccountKey return the account json converted by ParseAccountJson and its key, the private key
+// of a Web3 Secret Storage v3 json is decrypted only once while converting
+func parseAccountKey(accountJson, password string) (parsed string, key Key, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			key = nil
+			parsed, key = "", nil
 			err = errors.New("decrypt private key failed")
 		}
 	}()
 
+	if v3, ok := keystoreV3Account(accountJson); ok {
+		v3Json, ecdsaKey, err := parseKeystoreV3(v3, password)
+		if err != nil {
+			return "", nil, err
+		}
+		return v3Json, ecdsaKey, nil
+	}
 	accountJson, err = ParseAccountJson(accountJson, password)
 	if err != nil {
-		return nil, err
+		return "", nil, err
+	}
+	key, err = genKeyFromParsedJson(accountJson, password)
+	if err != nil {
+		return "", nil, err
 	}
+	return accountJson, key, nil
+}
 
+// genKeyFromParsedJson decrypt the key of the account json returned by ParseAccountJson
+func genKeyFromParsedJson(accountJson, password string) (Key, error) {
 	account := new(accountJSON)
-	err = json.Unmarshal([]byte(accountJson), account)
-	if err != nil {
+	if err := json.Unmarshal([]byte(accountJson), account); err != nil {
 		return nil, err
 	}
 	var priv []byte
-	priv, err = decryptPriv(account.PrivateKey, account.Algo, password)
+	var err error
+	if account.Crypto != nil {
+		priv, err = decryptKeystore(account.Crypto, password)
+	} else {
+		priv, err = decryptPriv(account.PrivateKey, account.Algo, password)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -383,6 +414,21 @@ func GenKeyFromAccountJson(accountJson, password string) (key interface{}, err e
 	return nil, errors.New("error account algo type")
 }
 
+// keystoreV3Account return the decoded account json if it is a Web3 Secret Storage v3 json
+func keystoreV3Account(accountJson string) (map[string]interface{}, bool) {
+	account := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(accountJson), &account); err != nil {
+		return nil, false
+	}
+	return account, isKeystoreV3(account)
+}
+
+// isKeystoreV3 return true if the account is a Web3 Secret Storage v3 json, whose version is a number
+func isKeystoreV3(account map[string]interface{}) bool {
+	v, ok := account["version"].(float64)
+	return ok && v == keystoreV3
+}
+
 func ParseAccountJson(accountJson, password string) (newAccountJson string, err error) {
 	account := make(map[string]interface{})
 	err = json.Unmarshal([]byte(accountJson), &account)
@@ -397,9 +443,16 @@ func ParseAccountJson(accountJson, password string) (newAccountJson string, err
 	var isEncrypted bool
 	var encodedCert string
 
+	if isKeystoreV3(account) {
+		newAccountJson, _, err = parseKeystoreV3(account, password)
+		return newAccountJson, err
+	}
+
 	address = account["address"].(string)
 	if account["encrypted"] == nil {
-		privateKey = strings.ToLower(account["privateKey"].(string))
+		// the accounts encrypted by kdf have no privateKey but the crypto section
+		privateKey, _ = account["privateKey"].(string)
+		privateKey = strings.ToLower(privateKey)
 	} else {
 		privateKey = strings.ToLower(account["encrypted"].(string))
 	}
@@ -489,6 +542,7 @@ func decryptPriv(encrypted, algo, password string) (priv []byte, err error) {
 	if strings.HasPrefix(algo, "0x0") {
 		switch algo {
 		case ECKDF2, ECKDF2R1:
+			// the legacy KDF2 account json without the crypto section
 			return nil, errors.New("not support KDF2 now")
 		case ECDES, ECDESR1:
 			priv, err = DesDecrypt(common.Hex2Bytes(encrypted), []byte(password))
@@ -224,7 +224,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 	}
 	switch account.Algo {
 	case ECAES:
-		ecKey := key.(*asym.ECDSAPrivateKey)
+		ecKey := key.(*ECDSAKey).ECDSAPrivateKey
 		pubBytes, _ := ecKey.Public().(*asym.ECDSAPublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes[1:])
 		address := h[12:]
@@ -236,7 +236,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 		assert.Equal(t, common.Bytes2Hex(data), common.Bytes2Hex(pkBytes))
 		return true
 	case ECDES:
-		ecKey := key.(*asym.ECDSAPrivateKey)
+		ecKey := key.(*ECDSAKey).ECDSAPrivateKey
 		pubBytes, _ := ecKey.Public().(*asym.ECDSAPublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes[1:])
 		address := h[12:]
@@ -248,7 +248,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 		assert.Equal(t, common.Bytes2Hex(data), common.Bytes2Hex(pkBytes))
 		return true
 	case ECRAW:
-		ecKey := key.(*asym.ECDSAPrivateKey)
+		ecKey := key.(*ECDSAKey).ECDSAPrivateKey
 		pubBytes, _ := ecKey.Public().(*asym.ECDSAPublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes[1:])
 		address := h[12:]
@@ -259,7 +259,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 		assert.Equal(t, account.PrivateKey, common.Bytes2Hex(pkBytes))
 		return true
 	case EC3DES:
-		ecKey := key.(*asym.ECDSAPrivateKey)
+		ecKey := key.(*ECDSAKey).ECDSAPrivateKey
 		pubBytes, _ := ecKey.Public().(*asym.ECDSAPublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes[1:])
 		address := h[12:]
@@ -272,7 +272,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 		return true
 
 	case SMSM4:
-		smKey := key.(*gm.SM2PrivateKey)
+		smKey := key.(*SM2Key).SM2PrivateKey
 		pubBytes, _ := smKey.Public().(*gm.SM2PublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes)
 		address := h[12:]
@@ -285,7 +285,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 		assert.Equal(t, account.PublicKey, common.Bytes2Hex(pubBytes))
 		return true
 	case SMDES:
-		smKey := key.(*gm.SM2PrivateKey)
+		smKey := key.(*SM2Key).SM2PrivateKey
 		pubBytes, _ := smKey.Public().(*gm.SM2PublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes)
 		address := h[12:]
@@ -298,7 +298,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 		assert.Equal(t, account.PublicKey, common.Bytes2Hex(pubBytes))
 		return true
 	case SMRAW:
-		smKey := key.(*gm.SM2PrivateKey)
+		smKey := key.(*SM2Key).SM2PrivateKey
 		pubBytes, _ := smKey.Public().(*gm.SM2PublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes)
 		address := h[12:]
@@ -310,7 +310,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 		assert.Equal(t, account.PublicKey, common.Bytes2Hex(pubBytes))
 		return true
 	case SMAES:
-		smKey := key.(*gm.SM2PrivateKey)
+		smKey := key.(*SM2Key).SM2PrivateKey
 		pubBytes, _ := smKey.Public().(*gm.SM2PublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes)
 		address := h[12:]
@@ -325,7 +325,7 @@ func verifyAccount(accountJson, password string, t *testing.T) bool {
 		assert.Equal(t, account.PublicKey, common.Bytes2Hex(pubBytes))
 		return true
 	case SM3DES:
-		smKey := key.(*gm.SM2PrivateKey)
+		smKey := key.(*SM2Key).SM2PrivateKey
 		pubBytes, _ := smKey.Public().(*gm.SM2PublicKey).Bytes()
 		h, _ := hash.NewHasher(hash.KECCAK_256).Hash(pubBytes)
 		address := h[12:]
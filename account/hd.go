@@ -0,0 +1,290 @@
+package account
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hyperchain/gosdk/common/math"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// HardenedKeyStart is the first index of hardened child keys, which are derived from the private key,
+	// so the leak of a child private key and the parent public key does not leak the parent private key
+	HardenedKeyStart uint32 = 0x80000000
+	// BIP44Purpose is the purpose of BIP44 paths
+	BIP44Purpose uint32 = 44
+	// CoinTypeETH is the SLIP-44 coin type of Ethereum, whose paths are used by most secp256k1 wallets
+	CoinTypeETH uint32 = 60
+)
+
+// the version bytes of BIP32 serialized extended keys of mainnet
+var (
+	xprvVersion = []byte{0x04, 0x88, 0xad, 0xe4}
+	xpubVersion = []byte{0x04, 0x88, 0xb2, 0x1e}
+)
+
+// ErrInvalidChild is returned with the probability lower than 1 in 2^127 when the derived key is invalid,
+// the next index should be used instead as BIP32 requires
+var ErrInvalidChild = errors.New("invalid child key, use the next index")
+
+// ExtendedKey is a private key with its chain code of BIP32 hierarchical deterministic derivation, so that
+// the keys of lots of accounts are derived from a single seed which is usually generated by a BIP39 mnemonic.
+// ECDSA secp256k1 keys are derived as BIP32. SM2 keys are derived the same way on the SM2 curve except that
+// the master key is derived by the hmac key "SM2 seed", like SLIP-10 does for the other curves.
+type ExtendedKey struct {
+	curve     *hdCurve
+	key       []byte
+	chainCode []byte
+	depth     uint8
+	parentFP  []byte
+	index     uint32
+}
+
+// NewMasterKey return the master key of seed for the accounts of algorithm, which is AlgorithmECDSAK1
+// or AlgorithmSM2. The seed is 16 to 64 bytes, such as the one returned by MnemonicToSeed.
+func NewMasterKey(seed []byte, algorithm Algorithm) (*ExtendedKey, error) {
+	var curve *hdCurve
+	switch algorithm {
+	case AlgorithmECDSAK1:
+		curve = secp256k1Curve
+	case AlgorithmSM2:
+		curve = sm2Curve
+	default:
+		return nil, fmt.Errorf("hierarchical deterministic derivation of %s is not supported", algorithm)
+	}
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errors.New("seed length must be between 16 and 64 bytes")
+	}
+
+	i := hmacSHA512([]byte(curve.seedKey), seed)
+	if !curve.validKey(new(big.Int).SetBytes(i[:32])) {
+		return nil, errors.New("invalid seed, use another one")
+	}
+	return &ExtendedKey{
+		curve:     curve,
+		key:       i[:32],
+		chainCode: i[32:],
+		parentFP:  make([]byte, 4),
+	}, nil
+}
+
+// Child return the child key of index, the index not less than HardenedKeyStart derives a hardened child
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.depth == 255 {
+		return nil, errors.New("cannot derive a key with more than 255 indices in its path")
+	}
+	data := make([]byte, 0, 37)
+	if index >= HardenedKeyStart {
+		data = append(data, 0)
+		data = append(data, k.key...)
+	} else {
+		pub, err := k.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, pub...)
+	}
+	data = append(data, uint32Bytes(index)...)
+
+	parentFP, err := k.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+
+	i := hmacSHA512(k.chainCode, data)
+	il := new(big.Int).SetBytes(i[:32])
+	if il.Cmp(k.curve.n) >= 0 {
+		return nil, ErrInvalidChild
+	}
+	child := il.Add(il, new(big.Int).SetBytes(k.key))
+	child.Mod(child, k.curve.n)
+	if child.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+	return &ExtendedKey{
+		curve:     k.curve,
+		key:       math.PaddedBigBytes(child, 32),
+		chainCode: i[32:],
+		depth:     k.depth + 1,
+		parentFP:  parentFP,
+		index:     index,
+	}, nil
+}
+
+// Derive return the key of path, such as "m/44'/60'/0'/0/0" from the master key, or "0/1" relative to k.
+// A hardened index is suffixed by ' or h.
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(strings.TrimSpace(path), "m") && k.depth != 0 {
+		return nil, errors.New("absolute path " + path + " should be derived from the master key")
+	}
+	key := k
+	for _, index := range indices {
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Key return the account key, which is *ECDSAKey or *SM2Key according to the algorithm
+func (k *ExtendedKey) Key() (Key, error) {
+	return k.curve.newKey(k.key)
+}
+
+// Algorithm return the signature algorithm of the derived accounts
+func (k *ExtendedKey) Algorithm() Algorithm {
+	return k.curve.algorithm
+}
+
+// Depth return the number of indices in the path of k, 0 for the master key
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// Index return the index of k in its parent
+func (k *ExtendedKey) Index() uint32 {
+	return k.index
+}
+
+// PublicKey return the compressed public key
+func (k *ExtendedKey) PublicKey() ([]byte, error) {
+	return k.curve.publicKey(k.key)
+}
+
+// Serialize return the BIP32 serialized private key "xprv...", which is defined for secp256k1 keys only
+func (k *ExtendedKey) Serialize() (string, error) {
+	return k.serialize(xprvVersion, append([]byte{0}, k.key...))
+}
+
+// SerializePublic return the BIP32 serialized public key "xpub...", which is defined for secp256k1 keys only
+func (k *ExtendedKey) SerializePublic() (string, error) {
+	pub, err := k.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	return k.serialize(xpubVersion, pub)
+}
+
+func (k *ExtendedKey) serialize(version, keyData []byte) (string, error) {
+	if k.curve != secp256k1Curve {
+		return "", errors.New("serialization of extended key is defined for secp256k1 only")
+	}
+	data := make([]byte, 0, 82)
+	data = append(data, version...)
+	data = append(data, k.depth)
+	data = append(data, k.parentFP...)
+	data = append(data, uint32Bytes(k.index)...)
+	data = append(data, k.chainCode...)
+	data = append(data, keyData...)
+	checksum := sha256.Sum256(data)
+	checksum = sha256.Sum256(checksum[:])
+	return base58Encode(append(data, checksum[:4]...)), nil
+}
+
+func (k *ExtendedKey) fingerprint() ([]byte, error) {
+	pub, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(pub)
+	h := ripemd160.New()
+	h.Write(sum[:])
+	return h.Sum(nil)[:4], nil
+}
+
+// ParsePath parse a derivation path like "m/44'/60'/0'/0/0" to the indices, the leading "m" is optional
+func ParsePath(path string) ([]uint32, error) {
+	path = strings.TrimSpace(path)
+	if path == "m" || path == "" {
+		return nil, nil
+	}
+	path = strings.TrimPrefix(path, "m/")
+	parts := strings.Split(path, "/")
+	indices := make([]uint32, len(parts))
+	for i, part := range parts {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid index %q of path %s", parts[i], path)
+		}
+		indices[i] = uint32(index)
+		if hardened {
+			indices[i] += HardenedKeyStart
+		}
+	}
+	return indices, nil
+}
+
+// BIP44Path return the BIP44 path m/44'/coinType'/account'/change/index, change is 0 for the
+// receiving addresses and 1 for the change ones
+func BIP44Path(coinType, account, change, index uint32) string {
+	return fmt.Sprintf("m/%d'/%d'/%d'/%d/%d", BIP44Purpose, coinType, account, change, index)
+}
+
+// NewKeyFromMnemonic derive the account key of path from the BIP39 mnemonic and passphrase,
+// algorithm is AlgorithmECDSAK1 or AlgorithmSM2
+func NewKeyFromMnemonic(mnemonic, passphrase string, algorithm Algorithm, path string) (Key, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	master, err := NewMasterKey(seed, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	extended, err := master.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	return extended.Key()
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func uint32Bytes(i uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, i)
+	return b
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func base58Encode(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	radix, mod := big.NewInt(58), new(big.Int)
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	// the leading zero bytes are encoded as '1'
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
@@ -0,0 +1,192 @@
+package account
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hyperchain/gosdk/common"
+	"github.com/hyperchain/gosdk/common/math"
+	"github.com/stretchr/testify/assert"
+)
+
+// the test vectors of BIP32
+var bip32Vectors = []struct {
+	seed string
+	keys []struct{ path, xprv, xpub string }
+}{
+	{
+		seed: "000102030405060708090a0b0c0d0e0f",
+		keys: []struct{ path, xprv, xpub string }{
+			{"m",
+				"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+				"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8"},
+			{"m/0'",
+				"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+				"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw"},
+			{"m/0'/1",
+				"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+				"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ"},
+			{"m/0'/1/2'",
+				"xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+				"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5"},
+			{"m/0'/1/2'/2",
+				"xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+				"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV"},
+			{"m/0'/1/2'/2/1000000000",
+				"xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+				"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy"},
+		},
+	},
+	{
+		seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+		keys: []struct{ path, xprv, xpub string }{
+			{"m",
+				"xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+				"xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB"},
+			{"m/0/2147483647h/1/2147483646h/2",
+				"xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+				"xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt"},
+		},
+	},
+	{
+		// the private key of m/0' has a leading zero byte
+		seed: "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+		keys: []struct{ path, xprv, xpub string }{
+			{"m",
+				"xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+				"xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13"},
+			{"m/0'",
+				"xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+				"xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y"},
+		},
+	},
+}
+
+func TestExtendedKey_BIP32Vectors(t *testing.T) {
+	for _, vector := range bip32Vectors {
+		master, err := NewMasterKey(common.Hex2Bytes(vector.seed), AlgorithmECDSAK1)
+		assert.Nil(t, err)
+		for _, expect := range vector.keys {
+			key, err := master.Derive(expect.path)
+			if !assert.Nil(t, err, expect.path) {
+				continue
+			}
+			xprv, err := key.Serialize()
+			assert.Nil(t, err)
+			assert.Equal(t, expect.xprv, xprv, expect.path)
+			xpub, err := key.SerializePublic()
+			assert.Nil(t, err)
+			assert.Equal(t, expect.xpub, xpub, expect.path)
+		}
+	}
+
+	// the relative path is derived from the key itself
+	master, _ := NewMasterKey(common.Hex2Bytes(bip32Vectors[0].seed), AlgorithmECDSAK1)
+	parent, err := master.Derive("m/0'/1")
+	if !assert.Nil(t, err) {
+		return
+	}
+	child, err := parent.Derive("2'/2")
+	if !assert.Nil(t, err) {
+		return
+	}
+	xprv, _ := child.Serialize()
+	assert.Equal(t, bip32Vectors[0].keys[4].xprv, xprv)
+	_, err = parent.Derive("m/0")
+	assert.NotNil(t, err)
+}
+
+func TestExtendedKey_SM2(t *testing.T) {
+	seed := common.Hex2Bytes(bip32Vectors[0].seed)
+	master, err := NewMasterKey(seed, AlgorithmSM2)
+	assert.Nil(t, err)
+	assert.Equal(t, AlgorithmSM2, master.Algorithm())
+	_, err = master.Serialize()
+	assert.NotNil(t, err)
+
+	path := BIP44Path(CoinTypeETH, 0, 0, 1)
+	assert.Equal(t, "m/44'/60'/0'/0/1", path)
+	derived, err := master.Derive(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, uint8(5), derived.Depth())
+	assert.Equal(t, uint32(1), derived.Index())
+
+	key, err := derived.Key()
+	assert.Nil(t, err)
+	_, ok := key.(*SM2Key)
+	assert.True(t, ok)
+
+	// the derivation is deterministic and differs from secp256k1
+	again, _ := NewMasterKey(seed, AlgorithmSM2)
+	againDerived, _ := again.Derive(path)
+	assert.Equal(t, derived.key, againDerived.key)
+	k1, _ := NewMasterKey(seed, AlgorithmECDSAK1)
+	k1Derived, _ := k1.Derive(path)
+	assert.NotEqual(t, derived.key, k1Derived.key)
+	k1Key, err := k1Derived.Key()
+	assert.Nil(t, err)
+	_, ok = k1Key.(*ECDSAKey)
+	assert.True(t, ok)
+
+	_, err = NewMasterKey(seed, AlgorithmED25519)
+	assert.NotNil(t, err)
+	_, err = NewMasterKey(seed[:8], AlgorithmSM2)
+	assert.NotNil(t, err)
+}
+
+func TestHDCurve(t *testing.T) {
+	// the public key of private key 1 is the base point
+	one := math.PaddedBigBytes(big.NewInt(1), 32)
+	for curve, g := range map[*hdCurve]string{
+		secp256k1Curve: "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		sm2Curve:       "0232c4ae2c1f1981195f9904466a39c9948fe30bbff2660be1715a4589334c74c7",
+	} {
+		pub, err := curve.publicKey(one)
+		assert.Nil(t, err, string(curve.algorithm))
+		assert.Equal(t, g, common.Bytes2Hex(pub), string(curve.algorithm))
+	}
+
+	pub, err := compressPublicKey(append([]byte{4}, append(make([]byte, 63), 0x03)...))
+	assert.Nil(t, err)
+	assert.Equal(t, append([]byte{3}, make([]byte, 32)...), pub)
+	_, err = compressPublicKey(make([]byte, 33))
+	assert.NotNil(t, err)
+}
+
+func TestParsePath(t *testing.T) {
+	indices, err := ParsePath("m/44'/60h/0H/0/1")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{HardenedKeyStart + 44, HardenedKeyStart + 60, HardenedKeyStart, 0, 1}, indices)
+	indices, err = ParsePath("m")
+	assert.Nil(t, err)
+	assert.Empty(t, indices)
+	for _, path := range []string{"m/", "m/a", "m/-1", "m/2147483648", "m/0''"} {
+		_, err = ParsePath(path)
+		assert.NotNil(t, err, path)
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	seed, err := MnemonicToSeed(mnemonic, "TREZOR")
+	assert.Nil(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", common.Bytes2Hex(seed))
+
+	// the checksum of the last word is wrong
+	assert.NotNil(t, ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"))
+	_, err = MnemonicToSeed("abandon hyperchain", "")
+	assert.NotNil(t, err)
+
+	generated, err := NewMnemonic(256)
+	assert.Nil(t, err)
+	assert.Nil(t, ValidateMnemonic(generated))
+	_, err = NewMnemonic(100)
+	assert.NotNil(t, err)
+
+	key, err := NewKeyFromMnemonic(generated, "", AlgorithmSM2, BIP44Path(CoinTypeETH, 0, 0, 0))
+	assert.Nil(t, err)
+	_, ok := key.(*SM2Key)
+	assert.True(t, ok)
+}
@@ -0,0 +1,81 @@
+package account
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/hyperchain/gosdk/common"
+)
+
+// hdCurve is the curve of the hierarchical deterministic derivation of its private keys, whose public keys
+// are computed by the crypto library of the algorithm
+type hdCurve struct {
+	algorithm Algorithm
+	// seedKey is the hmac key deriving the master key from seed
+	seedKey string
+	// n is the order of the base point
+	n *big.Int
+	// newKey return the account key of the 32 bytes private key
+	newKey func(key []byte) (Key, error)
+}
+
+var (
+	secp256k1Curve = &hdCurve{
+		algorithm: AlgorithmECDSAK1,
+		seedKey:   "Bitcoin seed",
+		n:         hexInt("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"),
+		newKey: func(key []byte) (Key, error) {
+			k, err := NewAccountFromPriv(common.Bytes2Hex(key))
+			if err != nil {
+				return nil, err
+			}
+			return k, nil
+		},
+	}
+	sm2Curve = &hdCurve{
+		algorithm: AlgorithmSM2,
+		seedKey:   "SM2 seed",
+		n:         hexInt("fffffffeffffffffffffffffffffffff7203df6b21c6052b53bbf40939d54123"),
+		newKey: func(key []byte) (Key, error) {
+			k, err := NewAccountSm2FromPriv(common.Bytes2Hex(key))
+			if err != nil {
+				return nil, err
+			}
+			return k, nil
+		},
+	}
+)
+
+func hexInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex integer " + s)
+	}
+	return n
+}
+
+// validKey return whether key is a private key in [1, n-1]
+func (c *hdCurve) validKey(key *big.Int) bool {
+	return key.Sign() > 0 && key.Cmp(c.n) < 0
+}
+
+// publicKey return the compressed public key of the private key
+func (c *hdCurve) publicKey(key []byte) ([]byte, error) {
+	k, err := c.newKey(key)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := k.PublicBytes()
+	if err != nil {
+		return nil, err
+	}
+	return compressPublicKey(pub)
+}
+
+// compressPublicKey compress the uncompressed public key 0x04 || x || y to (0x02 + y&1) || x
+func compressPublicKey(pub []byte) ([]byte, error) {
+	if len(pub) != 65 || pub[0] != 0x04 {
+		return nil, errors.New("invalid uncompressed public key")
+	}
+	return append([]byte{0x02 + pub[64]&1}, pub[1:33]...), nil
+}
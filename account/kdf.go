@@ -0,0 +1,292 @@
+package account
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperchain/gosdk/common"
+	"github.com/ultramesh/crypto-standard/hash"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// StandardScryptN and StandardScryptP are the scrypt parameters of Web3 Secret Storage,
+	// which take about 1 second and 256MB memory to derive a key
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+	// LightScryptN and LightScryptP take about 100 milliseconds and 4MB memory to derive a key
+	LightScryptN = 1 << 12
+	LightScryptP = 6
+	// StandardPBKDF2Iterations is the iteration count of PBKDF2 of Web3 Secret Storage
+	StandardPBKDF2Iterations = 262144
+
+	scryptR     = 8
+	kdfKeyLen   = 32
+	kdfScrypt   = "scrypt"
+	kdfPBKDF2   = "pbkdf2"
+	kdfPRF      = "hmac-sha256"
+	kdfCipher   = "aes-128-ctr"
+	keystoreV3  = 3
+	errKDFParam = "invalid kdf param %s"
+
+	// the limits of the kdf params read from account json, so that a crafted account json can not take
+	// gigabytes of memory or hours of cpu to derive its key
+	maxKDFKeyLen        = 64
+	maxScryptN          = 1 << 20
+	maxScryptRP         = 1 << 30
+	maxScryptMemory     = 1 << 30
+	maxPBKDF2Iterations = 1 << 22
+)
+
+// the parameters of the accounts encrypted by scrypt or PBKDF2 in NewAccountJson, they can be lowered
+// for the accounts which are not stored for long, such as in tests
+var (
+	ScryptN          = StandardScryptN
+	ScryptP          = StandardScryptP
+	PBKDF2Iterations = StandardPBKDF2Iterations
+)
+
+var errDecrypt = errors.New("could not decrypt key with given password")
+
+// keystoreCrypto is the crypto section of Web3 Secret Storage v3, the private key is encrypted by
+// aes-128-ctr with the first half of the key derived from password, and the mac is the keccak256
+// hash of the second half and the ciphertext
+type keystoreCrypto struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams keystoreCipherParams   `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type keystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+// kdfOfAlgo return the kdf of the account algo, false if the algo is not encrypted by a kdf
+func kdfOfAlgo(algo string) (string, bool) {
+	switch algo {
+	case ECKDF2, ECKDF2R1, SMKDF2, ED25519KDF2:
+		return kdfPBKDF2, true
+	case ECSCRYPT, ECSCRYPTR1, SMSCRYPT, ED25519SCRYPT:
+		return kdfScrypt, true
+	default:
+		return "", false
+	}
+}
+
+// encryptKeystore encrypt the private key by the kdf of algo with random salt and iv
+func encryptKeystore(priv []byte, password, algo string) (*keystoreCrypto, error) {
+	kdf, ok := kdfOfAlgo(algo)
+	if !ok {
+		return nil, errors.New("not support crypt type " + algo)
+	}
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"dklen": kdfKeyLen,
+		"salt":  common.Bytes2Hex(salt),
+	}
+	if kdf == kdfScrypt {
+		params["n"] = ScryptN
+		params["r"] = scryptR
+		params["p"] = ScryptP
+	} else {
+		params["c"] = PBKDF2Iterations
+		params["prf"] = kdfPRF
+	}
+	c := &keystoreCrypto{
+		Cipher:       kdfCipher,
+		CipherParams: keystoreCipherParams{IV: common.Bytes2Hex(iv)},
+		KDF:          kdf,
+		KDFParams:    params,
+	}
+	derivedKey, err := c.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTR(derivedKey[:16], iv, priv)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := keystoreMAC(derivedKey, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	c.CipherText = common.Bytes2Hex(cipherText)
+	c.MAC = common.Bytes2Hex(mac)
+	return c, nil
+}
+
+// decryptKeystore verify the mac by password and return the decrypted private key
+func decryptKeystore(c *keystoreCrypto, password string) ([]byte, error) {
+	if c.Cipher != kdfCipher {
+		return nil, errors.New("not support cipher " + c.Cipher)
+	}
+	derivedKey, err := c.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	cipherText := common.Hex2Bytes(c.CipherText)
+	mac, err := keystoreMAC(derivedKey, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(mac, common.Hex2Bytes(c.MAC)) != 1 {
+		return nil, errDecrypt
+	}
+	return aesCTR(derivedKey[:16], common.Hex2Bytes(c.CipherParams.IV), cipherText)
+}
+
+func (c *keystoreCrypto) deriveKey(password string) ([]byte, error) {
+	salt, ok := c.KDFParams["salt"].(string)
+	if !ok {
+		return nil, fmt.Errorf(errKDFParam, "salt")
+	}
+	dkLen, err := c.intParam("dklen", maxKDFKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	if dkLen < 32 {
+		return nil, fmt.Errorf(errKDFParam, "dklen")
+	}
+	switch c.KDF {
+	case kdfScrypt:
+		n, err := c.intParam("n", maxScryptN)
+		if err != nil {
+			return nil, err
+		}
+		r, err := c.intParam("r", maxScryptRP-1)
+		if err != nil {
+			return nil, err
+		}
+		p, err := c.intParam("p", maxScryptRP-1)
+		if err != nil {
+			return nil, err
+		}
+		// scrypt takes 128*n*r bytes of memory and r*p times of the work of one block mixing
+		if uint64(r)*uint64(p) >= maxScryptRP {
+			return nil, fmt.Errorf(errKDFParam, "p")
+		}
+		if uint64(n)*uint64(r)*128 > maxScryptMemory {
+			return nil, fmt.Errorf(errKDFParam, "r")
+		}
+		return scrypt.Key([]byte(password), common.Hex2Bytes(salt), n, r, p, dkLen)
+	case kdfPBKDF2:
+		if prf, _ := c.KDFParams["prf"].(string); prf != kdfPRF {
+			return nil, errors.New("not support prf " + prf)
+		}
+		iterations, err := c.intParam("c", maxPBKDF2Iterations)
+		if err != nil {
+			return nil, err
+		}
+		return pbkdf2.Key([]byte(password), common.Hex2Bytes(salt), iterations, dkLen, sha256.New), nil
+	default:
+		return nil, errors.New("not support kdf " + c.KDF)
+	}
+}
+
+// intParam return a kdf param in [1, limit], which is float64 if it is decoded from json
+func (c *keystoreCrypto) intParam(name string, limit int) (int, error) {
+	var v int
+	switch param := c.KDFParams[name].(type) {
+	case int:
+		v = param
+	case float64:
+		if param > float64(limit) {
+			return 0, fmt.Errorf(errKDFParam, name)
+		}
+		v = int(param)
+	case json.Number:
+		n, err := param.Int64()
+		if err != nil || n > int64(limit) {
+			return 0, fmt.Errorf(errKDFParam, name)
+		}
+		v = int(n)
+	}
+	if v <= 0 || v > limit {
+		return 0, fmt.Errorf(errKDFParam, name)
+	}
+	return v, nil
+}
+
+func aesCTR(key, iv, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errors.New("invalid iv length")
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+func keystoreMAC(derivedKey, cipherText []byte) ([]byte, error) {
+	data := make([]byte, 0, 16+len(cipherText))
+	data = append(data, derivedKey[16:32]...)
+	data = append(data, cipherText...)
+	return hash.NewHasher(hash.KECCAK_256).Hash(data)
+}
+
+// parseKeystoreV3 convert a Web3 Secret Storage v3 json of ecdsa key to the account json of version 4.0,
+// and return the key decrypted while converting, so that it is not decrypted again by the kdf
+func parseKeystoreV3(account map[string]interface{}, password string) (string, *ECDSAKey, error) {
+	section, ok := account["crypto"]
+	if !ok {
+		// some implementations capitalize it
+		section = account["Crypto"]
+	}
+	data, err := json.Marshal(section)
+	if err != nil {
+		return "", nil, err
+	}
+	c := new(keystoreCrypto)
+	if err := json.Unmarshal(data, c); err != nil {
+		return "", nil, err
+	}
+	var algo string
+	switch c.KDF {
+	case kdfScrypt:
+		algo = ECSCRYPT
+	case kdfPBKDF2:
+		algo = ECKDF2
+	default:
+		return "", nil, errors.New("not support kdf " + c.KDF)
+	}
+
+	priv, err := decryptKeystore(c, password)
+	if err != nil {
+		return "", nil, err
+	}
+	key, err := NewAccountFromPriv(common.Bytes2Hex(priv))
+	if err != nil {
+		return "", nil, err
+	}
+	accountJson := &accountJSON{Algo: algo, Version: V4, Crypto: c}
+	accountJson.Address, accountJson.PublicKey = getAddressAndPublic(key)
+	if address, ok := account["address"].(string); ok && common.HexToAddress(address) != accountJson.Address {
+		return "", nil, errors.New("parse ecdsa key error, address is inconsistent")
+	}
+	jsonBytes, err := json.Marshal(accountJson)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(jsonBytes), key, nil
+}
@@ -0,0 +1,170 @@
+package account
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperchain/gosdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// the test vectors of Web3 Secret Storage v3, both encrypt the same key by password "testpassword"
+const (
+	keystoreV3Priv   = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	keystoreV3PBKDF2 = `{
+		"crypto" : {
+			"cipher" : "aes-128-ctr",
+			"cipherparams" : {"iv" : "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext" : "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf" : "pbkdf2",
+			"kdfparams" : {
+				"c" : 262144,
+				"dklen" : 32,
+				"prf" : "hmac-sha256",
+				"salt" : "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+			},
+			"mac" : "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id" : "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version" : 3
+	}`
+	keystoreV3Scrypt = `{
+		"crypto" : {
+			"cipher" : "aes-128-ctr",
+			"cipherparams" : {"iv" : "83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext" : "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf" : "scrypt",
+			"kdfparams" : {
+				"dklen" : 32,
+				"n" : 262144,
+				"r" : 1,
+				"p" : 8,
+				"salt" : "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"
+			},
+			"mac" : "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id" : "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version" : 3
+	}`
+)
+
+func TestDecryptKeystore_V3Vectors(t *testing.T) {
+	for algo, v3 := range map[string]string{ECKDF2: keystoreV3PBKDF2, ECSCRYPT: keystoreV3Scrypt} {
+		var keystore struct {
+			Crypto *keystoreCrypto `json:"crypto"`
+		}
+		assert.Nil(t, json.Unmarshal([]byte(v3), &keystore))
+		priv, err := decryptKeystore(keystore.Crypto, "testpassword")
+		assert.Nil(t, err)
+		assert.Equal(t, keystoreV3Priv, common.Bytes2Hex(priv))
+		_, err = decryptKeystore(keystore.Crypto, "wrongpassword")
+		assert.Equal(t, errDecrypt, err)
+
+		// the v3 json is converted to the account json of 4.0 keeping the crypto section
+		accountJson, err := ParseAccountJson(v3, "testpassword")
+		assert.Nil(t, err)
+		account := new(accountJSON)
+		assert.Nil(t, json.Unmarshal([]byte(accountJson), account))
+		assert.Equal(t, algo, account.Algo)
+		assert.Equal(t, V4, account.Version)
+		assert.Equal(t, keystore.Crypto.MAC, account.Crypto.MAC)
+
+		key, err := GenKeyFromAccountJson(v3, "testpassword")
+		assert.Nil(t, err)
+		ecdsaKey, ok := key.(*ECDSAKey)
+		assert.True(t, ok)
+		assert.Equal(t, account.Address, ecdsaKey.GetAddress())
+		_, err = GenKeyFromAccountJson(accountJson, "wrongpassword")
+		assert.NotNil(t, err)
+
+		// the key decrypted while converting is returned with the account json
+		parsed, key, err := parseAccountKey(v3, "testpassword")
+		assert.Nil(t, err)
+		assert.Equal(t, accountJson, parsed)
+		assert.Equal(t, ecdsaKey.GetAddress(), key.(*ECDSAKey).GetAddress())
+		_, _, err = parseAccountKey(v3, "wrongpassword")
+		assert.Equal(t, errDecrypt, err)
+	}
+}
+
+func TestNewAccountJson_KDF(t *testing.T) {
+	defer func(n, p, c int) {
+		ScryptN, ScryptP, PBKDF2Iterations = n, p, c
+	}(ScryptN, ScryptP, PBKDF2Iterations)
+	ScryptN, ScryptP, PBKDF2Iterations = LightScryptN, 1, 1024
+
+	for _, acType := range []string{SMKDF2, SMSCRYPT, ED25519KDF2, ED25519SCRYPT} {
+		accountJson, err := NewAccountJson(acType, "12345678")
+		assert.Nil(t, err, acType)
+		account := new(accountJSON)
+		assert.Nil(t, json.Unmarshal([]byte(accountJson), account))
+		assert.Equal(t, acType, account.Algo)
+		assert.Empty(t, account.PrivateKey)
+		assert.Equal(t, kdfCipher, account.Crypto.Cipher)
+
+		parsed, err := ParseAccountJson(accountJson, "12345678")
+		assert.Nil(t, err)
+		assert.Equal(t, accountJson, parsed)
+		key, err := GenKeyFromAccountJson(accountJson, "12345678")
+		assert.Nil(t, err, acType)
+		switch k := key.(type) {
+		case *SM2Key:
+			assert.Equal(t, account.Address, k.GetAddress())
+		case *ED25519Key:
+			assert.Equal(t, account.Address, k.GetAddress())
+		default:
+			t.Errorf("unexpected key %T of %s", key, acType)
+		}
+		_, err = GenKeyFromAccountJson(accountJson, "87654321")
+		assert.NotNil(t, err)
+	}
+}
+
+func TestEncryptKeystore(t *testing.T) {
+	defer func(n int) { ScryptN = n }(ScryptN)
+	ScryptN = LightScryptN
+
+	priv := common.Hex2Bytes(keystoreV3Priv)
+	c, err := encryptKeystore(priv, "", ECSCRYPTR1)
+	assert.Nil(t, err)
+	assert.Equal(t, kdfScrypt, c.KDF)
+	decrypted, err := decryptKeystore(c, "")
+	assert.Nil(t, err)
+	assert.Equal(t, priv, decrypted)
+
+	// the params are read back from json
+	data, err := json.Marshal(c)
+	assert.Nil(t, err)
+	parsed := new(keystoreCrypto)
+	assert.Nil(t, json.Unmarshal(data, parsed))
+	decrypted, err = decryptKeystore(parsed, "")
+	assert.Nil(t, err)
+	assert.Equal(t, priv, decrypted)
+
+	parsed.KDFParams["n"] = 0.0
+	_, err = decryptKeystore(parsed, "")
+	assert.EqualError(t, err, "invalid kdf param n")
+
+	// the params costing too much memory or cpu are rejected before deriving the key
+	for name, params := range map[string]map[string]interface{}{
+		"n":     {"n": float64(1 << 21)},
+		"p":     {"r": 8.0, "p": float64(1 << 27)},
+		"r":     {"n": float64(1 << 20), "r": 16.0},
+		"dklen": {"dklen": 1e30},
+	} {
+		bad := *parsed
+		bad.KDFParams = map[string]interface{}{"salt": parsed.KDFParams["salt"], "dklen": 32.0, "n": 1024.0, "r": 8.0, "p": 1.0}
+		for k, v := range params {
+			bad.KDFParams[k] = v
+		}
+		_, err = decryptKeystore(&bad, "")
+		assert.EqualError(t, err, "invalid kdf param "+name)
+	}
+	pbkdf2Crypto := &keystoreCrypto{KDF: kdfPBKDF2, KDFParams: map[string]interface{}{
+		"salt": parsed.KDFParams["salt"], "dklen": 32.0, "prf": kdfPRF, "c": float64(1 << 30)}}
+	_, err = pbkdf2Crypto.deriveKey("")
+	assert.EqualError(t, err, "invalid kdf param c")
+
+	_, err = encryptKeystore(priv, "", ECDES)
+	assert.NotNil(t, err)
+}
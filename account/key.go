@@ -2,6 +2,8 @@ package account
 
 import (
 	"crypto"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"github.com/hyperchain/gosdk/common"
 	gm "github.com/ultramesh/crypto-gm"
@@ -9,16 +11,29 @@ import (
 	"github.com/ultramesh/crypto-standard/ed25519"
 	"github.com/ultramesh/crypto-standard/hash"
 	"github.com/ultramesh/flato-msp-cert/primitives/x509"
+	"io"
 )
 
-//Key account key
+// Key account key, it is a crypto.Signer as well as a Signer, so it is accepted everywhere a Signer is,
+// such as Transaction.Sign
 type Key interface {
 	crypto.Signer
-	GetAddress() common.Address
-	PublicBytes() ([]byte, error)
+	Signer
+	// Verify return whether signature is signed by the key for digest, which is hashed as Digest
+	Verify(digest, signature []byte) (bool, error)
+	// PublicKeyHex return the hex of PublicBytes with 0x prefix
+	PublicKeyHex() string
 	PrivateBytes() ([]byte, error)
 }
 
+func publicKeyHex(key Key) string {
+	pub, err := key.PublicBytes()
+	if err != nil {
+		return ""
+	}
+	return common.ToHex(pub)
+}
+
 type PKIKey struct {
 	sk             crypto.Signer
 	addr           common.Address
@@ -72,6 +87,50 @@ func (key *PKIKey) PrivateBytes() ([]byte, error) {
 	}
 }
 
+func (key *PKIKey) Algorithm() Algorithm {
+	if _, ok := key.sk.(*gm.SM2PrivateKey); ok {
+		return AlgorithmPKISM2
+	}
+	return AlgorithmPKIECDSA
+}
+
+// Public return the public key in the certificate
+func (key *PKIKey) Public() crypto.PublicKey {
+	return key.cert.PublicKey
+}
+
+// Sign sign the digest by the private key, so PKIKey is a crypto.Signer
+func (key *PKIKey) Sign(reader io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return key.sk.Sign(reader, digest, opts)
+}
+
+func (key *PKIKey) SignDigest(digest []byte) ([]byte, error) {
+	return key.sk.Sign(rand.Reader, digest, nil)
+}
+
+// SignDigestBatch sign with batch flag if the key is SM2, otherwise it is the same as SignDigest
+func (key *PKIKey) SignDigestBatch(digest []byte) ([]byte, error) {
+	if sm2Key, ok := key.sk.(*gm.SM2PrivateKey); ok {
+		return sm2Key.SignBatch(rand.Reader, digest, nil)
+	}
+	return key.SignDigest(digest)
+}
+
+func (key *PKIKey) Verify(digest, signature []byte) (bool, error) {
+	switch pk := key.cert.PublicKey.(type) {
+	case *asym.ECDSAPublicKey:
+		return pk.Verify(nil, signature, digest)
+	case *gm.SM2PublicKey:
+		return pk.Verify(nil, signature, digest)
+	default:
+		return false, fmt.Errorf("unknown key type or nil")
+	}
+}
+
+func (key *PKIKey) PublicKeyHex() string {
+	return publicKeyHex(key)
+}
+
 type ECDSAKey struct {
 	*asym.ECDSAPrivateKey
 }
@@ -102,6 +161,35 @@ func (key *ECDSAKey) PrivateBytes() ([]byte, error) {
 	return key.Bytes()
 }
 
+func (key *ECDSAKey) Algorithm() Algorithm {
+	if key.AlgorithmType() == asym.AlgoP256R1 {
+		return AlgorithmECDSAR1
+	}
+	return AlgorithmECDSAK1
+}
+
+// SignDigest sign the digest, the signature of secp256k1 is recoverable
+func (key *ECDSAKey) SignDigest(digest []byte) ([]byte, error) {
+	return key.ECDSAPrivateKey.Sign(rand.Reader, digest, nil)
+}
+
+func (key *ECDSAKey) Verify(digest, signature []byte) (bool, error) {
+	if key.AlgorithmType() == asym.AlgoP256R1 {
+		return key.ECDSAPublicKey.Verify(nil, signature, digest)
+	}
+	// the recoverable signature is verified against the address
+	address := key.GetAddress()
+	pub, err := new(asym.ECDSAPublicKey).FromBytes(address[:], asym.AlgoP256K1Recover)
+	if err != nil {
+		return false, err
+	}
+	return pub.Verify(nil, signature, digest)
+}
+
+func (key *ECDSAKey) PublicKeyHex() string {
+	return publicKeyHex(key)
+}
+
 type SM2Key struct {
 	*gm.SM2PrivateKey
 }
@@ -123,6 +211,27 @@ func (key *SM2Key) PrivateBytes() ([]byte, error) {
 	return key.Bytes()
 }
 
+func (key *SM2Key) Algorithm() Algorithm {
+	return AlgorithmSM2
+}
+
+func (key *SM2Key) SignDigest(digest []byte) ([]byte, error) {
+	return key.SM2PrivateKey.Sign(rand.Reader, digest, nil)
+}
+
+// SignDigestBatch sign the digest with batch flag, so SM2Key is a BatchSigner
+func (key *SM2Key) SignDigestBatch(digest []byte) ([]byte, error) {
+	return key.SM2PrivateKey.SignBatch(rand.Reader, digest, nil)
+}
+
+func (key *SM2Key) Verify(digest, signature []byte) (bool, error) {
+	return key.PublicKey.Verify(nil, signature, digest)
+}
+
+func (key *SM2Key) PublicKeyHex() string {
+	return publicKeyHex(key)
+}
+
 type ED25519Key struct {
 	*ed25519.EDDSAPrivateKey
 }
@@ -140,3 +249,24 @@ func (key *ED25519Key) PublicBytes() ([]byte, error) {
 func (key *ED25519Key) PrivateBytes() ([]byte, error) {
 	return key.Bytes()
 }
+
+func (key *ED25519Key) Algorithm() Algorithm {
+	return AlgorithmED25519
+}
+
+// SignDigest sign the data itself, ed25519 hashes it while signing
+func (key *ED25519Key) SignDigest(digest []byte) ([]byte, error) {
+	return key.EDDSAPrivateKey.Sign(rand.Reader, digest, nil)
+}
+
+func (key *ED25519Key) Verify(digest, signature []byte) (bool, error) {
+	pub, ok := key.Public().(*ed25519.EDDSAPublicKey)
+	if !ok {
+		return false, errors.New("invalid ed25519 public key")
+	}
+	return pub.Verify(nil, signature, digest)
+}
+
+func (key *ED25519Key) PublicKeyHex() string {
+	return publicKeyHex(key)
+}
@@ -0,0 +1,64 @@
+package account
+
+import (
+	"crypto"
+	"crypto/rand"
+	"testing"
+
+	"github.com/hyperchain/gosdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// the keys are still crypto.Signer, so they can be passed to x509 and tls
+var (
+	_ crypto.Signer = (*ECDSAKey)(nil)
+	_ crypto.Signer = (*SM2Key)(nil)
+	_ crypto.Signer = (*ED25519Key)(nil)
+	_ Key           = (*PKIKey)(nil)
+)
+
+func TestKey_SignVerify(t *testing.T) {
+	k1, err := NewAccountFromPriv(keystoreV3Priv)
+	assert.Nil(t, err)
+	sm2, err := NewAccountSm2FromPriv(keystoreV3Priv)
+	assert.Nil(t, err)
+	ed, err := GenKeyFromAccountJson(mustAccountJSON(t, ED25519RAW), "")
+	assert.Nil(t, err)
+
+	for algorithm, key := range map[Algorithm]Key{
+		AlgorithmECDSAK1: k1,
+		AlgorithmSM2:     sm2,
+		AlgorithmED25519: ed,
+	} {
+		assert.Equal(t, algorithm, key.Algorithm())
+		pub, err := key.PublicBytes()
+		assert.Nil(t, err)
+		assert.Equal(t, common.ToHex(pub), key.PublicKeyHex())
+
+		digest, err := Digest(key, []byte("data"))
+		assert.Nil(t, err)
+		sig, err := key.SignDigest(digest)
+		assert.Nil(t, err, algorithm)
+		assert.NotEmpty(t, sig)
+		valid, err := key.Verify(digest, sig)
+		assert.Nil(t, err, algorithm)
+		assert.True(t, valid, algorithm)
+
+		// Sign of crypto.Signer signs the same digest
+		sig, err = key.Sign(rand.Reader, digest, nil)
+		assert.Nil(t, err, algorithm)
+		valid, err = key.Verify(digest, sig)
+		assert.Nil(t, err, algorithm)
+		assert.True(t, valid, algorithm)
+	}
+
+	// SM2 signs with batch flag, which is verified in the same way
+	var batch BatchSigner = sm2
+	digest, err := Digest(sm2, []byte("data"))
+	assert.Nil(t, err)
+	sig, err := batch.SignDigestBatch(digest)
+	assert.Nil(t, err)
+	valid, err := sm2.Verify(digest, sig)
+	assert.Nil(t, err)
+	assert.True(t, valid)
+}
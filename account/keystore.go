@@ -0,0 +1,307 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperchain/gosdk/common"
+)
+
+var (
+	// ErrAccountNotFound is returned when the account is not in the keystore
+	ErrAccountNotFound = errors.New("account not found in keystore")
+	// ErrAccountExists is returned when the imported account is in the keystore already
+	ErrAccountExists = errors.New("account exists in keystore")
+	// ErrLocked is returned when signing by a locked account, whose unlock expired or used up
+	ErrLocked = errors.New("account is locked")
+)
+
+// Keystore keeps account json files in a directory, such as conf/keystore, each file is named by the address
+// of account. An account is unlocked by its password to sign for a bounded duration or number of signatures,
+// the private key is only kept in memory until then. Keystore is safe for concurrent use.
+type Keystore struct {
+	dir      string
+	mutex    sync.RWMutex
+	unlocked map[common.Address]*unlockedKey
+}
+
+type unlockedKey struct {
+	signer Signer
+	// remaining is the number of signatures left, 0 is unlimited
+	remaining int
+	timer     *time.Timer
+}
+
+// NewKeystore return the keystore of dir, which is created if not exists
+func NewKeystore(dir string) (*Keystore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &Keystore{
+		dir:      dir,
+		unlocked: make(map[common.Address]*unlockedKey),
+	}, nil
+}
+
+// NewAccount generate an account of acType as NewAccountJson and store it
+func (ks *Keystore) NewAccount(acType, password string) (common.Address, error) {
+	accountJson, err := NewAccountJson(acType, password)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return ks.Import(accountJson, password)
+}
+
+// Import store the account json, which is converted to the current version as ParseAccountJson,
+// the password must decrypt the private key
+func (ks *Keystore) Import(accountJson, password string) (common.Address, error) {
+	accountJson, _, err := parseAccountKey(accountJson, password)
+	if err != nil {
+		return common.Address{}, err
+	}
+	account := new(accountJSON)
+	if err = json.Unmarshal([]byte(accountJson), account); err != nil {
+		return common.Address{}, err
+	}
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	if _, err = os.Stat(ks.path(account.Address)); err == nil {
+		return common.Address{}, ErrAccountExists
+	}
+	if err = writeFileAtomic(ks.path(account.Address), []byte(accountJson)); err != nil {
+		return common.Address{}, err
+	}
+	return account.Address, nil
+}
+
+// Export return the account json of address
+func (ks *Keystore) Export(address common.Address) (string, error) {
+	ks.mutex.RLock()
+	defer ks.mutex.RUnlock()
+	return ks.read(address)
+}
+
+// Delete lock the account and remove its file, the password must decrypt the private key
+func (ks *Keystore) Delete(address common.Address, password string) error {
+	accountJson, err := ks.Export(address)
+	if err != nil {
+		return err
+	}
+	if _, err = GenKeyFromAccountJson(accountJson, password); err != nil {
+		return err
+	}
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	ks.lock(address)
+	err = os.Remove(ks.path(address))
+	if os.IsNotExist(err) {
+		return ErrAccountNotFound
+	}
+	return err
+}
+
+// Accounts return the addresses of accounts in the keystore in order, the files not named by
+// the lower case hex of an address, as the keystore writes, are ignored
+func (ks *Keystore) Accounts() ([]common.Address, error) {
+	ks.mutex.RLock()
+	defer ks.mutex.RUnlock()
+	files, err := ioutil.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+	var addresses []common.Address
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), "0x") || !common.IsHexAddress(file.Name()) {
+			continue
+		}
+		address := common.HexToAddress(file.Name())
+		if file.Name() != address.Hex() {
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+	return addresses, nil
+}
+
+// Unlock decrypt the account by password, so that the Signer of account signs until timeout or signs
+// signatures, 0 means no limit. Unlocking an unlocked account resets its limits.
+func (ks *Keystore) Unlock(address common.Address, password string, timeout time.Duration, signs int) error {
+	if timeout < 0 || signs < 0 {
+		return errors.New("timeout and signs of unlock must not be negative")
+	}
+	accountJson, err := ks.Export(address)
+	if err != nil {
+		return err
+	}
+	key, err := GenKeyFromAccountJson(accountJson, password)
+	if err != nil {
+		return err
+	}
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	ks.lock(address)
+	u := &unlockedKey{signer: key, remaining: signs}
+	if timeout > 0 {
+		u.timer = time.AfterFunc(timeout, func() {
+			ks.mutex.Lock()
+			defer ks.mutex.Unlock()
+			// the account may be unlocked again in the meantime
+			if ks.unlocked[address] == u {
+				delete(ks.unlocked, address)
+			}
+		})
+	}
+	ks.unlocked[address] = u
+	return nil
+}
+
+// Lock drop the private key of the account from memory
+func (ks *Keystore) Lock(address common.Address) {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	ks.lock(address)
+}
+
+// IsUnlocked return whether the account is unlocked
+func (ks *Keystore) IsUnlocked(address common.Address) bool {
+	ks.mutex.RLock()
+	defer ks.mutex.RUnlock()
+	return ks.unlocked[address] != nil
+}
+
+// Signer return the Signer of the unlocked account, it returns ErrLocked once the account is locked.
+// The Signer of an SM2 account is a BatchSigner.
+func (ks *Keystore) Signer(address common.Address) (Signer, error) {
+	ks.mutex.RLock()
+	u := ks.unlocked[address]
+	ks.mutex.RUnlock()
+	if u == nil {
+		return nil, ErrLocked
+	}
+	public, err := u.signer.PublicBytes()
+	if err != nil {
+		return nil, err
+	}
+	s := &keystoreSigner{ks: ks, address: address, algorithm: u.signer.Algorithm(), public: public}
+	if _, ok := u.signer.(BatchSigner); ok {
+		return &keystoreBatchSigner{s}, nil
+	}
+	return s, nil
+}
+
+// acquire return the signer of the unlocked account counting a signature
+func (ks *Keystore) acquire(address common.Address) (Signer, error) {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	u := ks.unlocked[address]
+	if u == nil {
+		return nil, ErrLocked
+	}
+	if u.remaining > 0 {
+		u.remaining--
+		if u.remaining == 0 {
+			ks.lock(address)
+		}
+	}
+	return u.signer, nil
+}
+
+func (ks *Keystore) lock(address common.Address) {
+	if u := ks.unlocked[address]; u != nil {
+		if u.timer != nil {
+			u.timer.Stop()
+		}
+		delete(ks.unlocked, address)
+	}
+}
+
+func (ks *Keystore) read(address common.Address) (string, error) {
+	data, err := ioutil.ReadFile(ks.path(address))
+	if os.IsNotExist(err) {
+		return "", ErrAccountNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (ks *Keystore) path(address common.Address) string {
+	return filepath.Join(ks.dir, address.Hex())
+}
+
+// writeFileAtomic write a temporary file and rename it, so the file is never read partly written
+func writeFileAtomic(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// keystoreSigner signs by an unlocked account of keystore
+type keystoreSigner struct {
+	ks        *Keystore
+	address   common.Address
+	algorithm Algorithm
+	public    []byte
+}
+
+func (s *keystoreSigner) GetAddress() common.Address {
+	return s.address
+}
+
+func (s *keystoreSigner) Algorithm() Algorithm {
+	return s.algorithm
+}
+
+func (s *keystoreSigner) PublicBytes() ([]byte, error) {
+	return s.public, nil
+}
+
+func (s *keystoreSigner) SignDigest(digest []byte) ([]byte, error) {
+	signer, err := s.ks.acquire(s.address)
+	if err != nil {
+		return nil, err
+	}
+	return signer.SignDigest(digest)
+}
+
+// keystoreBatchSigner is the keystoreSigner of SM2 accounts
+type keystoreBatchSigner struct {
+	*keystoreSigner
+}
+
+func (s *keystoreBatchSigner) SignDigestBatch(digest []byte) ([]byte, error) {
+	signer, err := s.ks.acquire(s.address)
+	if err != nil {
+		return nil, err
+	}
+	if bs, ok := signer.(BatchSigner); ok {
+		return bs.SignDigestBatch(digest)
+	}
+	return signer.SignDigest(digest)
+}
@@ -0,0 +1,155 @@
+package account
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperchain/gosdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeystore(t *testing.T) (*Keystore, string) {
+	dir, err := ioutil.TempDir("", "gosdk-keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := NewKeystore(filepath.Join(dir, "keystore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ks, dir
+}
+
+func mustExport(t *testing.T, ks *Keystore, address common.Address) string {
+	accountJson, err := ks.Export(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return accountJson
+}
+
+func lightKDF() func() {
+	n, p, c := ScryptN, ScryptP, PBKDF2Iterations
+	ScryptN, ScryptP, PBKDF2Iterations = LightScryptN, 1, 1024
+	return func() {
+		ScryptN, ScryptP, PBKDF2Iterations = n, p, c
+	}
+}
+
+func TestKeystore_Accounts(t *testing.T) {
+	ks, dir := newTestKeystore(t)
+	defer os.RemoveAll(dir)
+	defer lightKDF()()
+	sm2, err := ks.NewAccount(SMKDF2, "12345678")
+	assert.Nil(t, err)
+	ed, err := ks.NewAccount(ED25519SCRYPT, "12345678")
+	assert.Nil(t, err)
+	ecdsa, err := ks.Import(keystoreV3PBKDF2, "testpassword")
+	assert.Nil(t, err)
+	_, err = ks.Import(keystoreV3PBKDF2, "testpassword")
+	assert.Equal(t, ErrAccountExists, err)
+
+	// the other files are ignored
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ks.dir, "README"), []byte("keys"), 0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ks.dir, "0xFC546CB6C1B3B1B6A7E2B4D6B5C2F2A8D0E3C1A9"), []byte(mustExport(t, ks, sm2)), 0600))
+	accounts, err := ks.Accounts()
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 3)
+	for _, address := range []interface{}{sm2, ed, ecdsa} {
+		assert.Contains(t, accounts, address)
+	}
+
+	exported, err := ks.Export(sm2)
+	assert.Nil(t, err)
+	key, err := GenKeyFromAccountJson(exported, "12345678")
+	assert.Nil(t, err)
+	assert.Equal(t, sm2, key.(Key).GetAddress())
+
+	assert.NotNil(t, ks.Delete(ed, "87654321"))
+	assert.Nil(t, ks.Delete(ed, "12345678"))
+	_, err = ks.Export(ed)
+	assert.Equal(t, ErrAccountNotFound, err)
+	assert.Equal(t, ErrAccountNotFound, ks.Unlock(ed, "12345678", 0, 0))
+	accounts, err = ks.Accounts()
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 2)
+}
+
+func TestKeystore_Unlock(t *testing.T) {
+	ks, dir := newTestKeystore(t)
+	defer os.RemoveAll(dir)
+	defer lightKDF()()
+	address, err := ks.NewAccount(SMKDF2, "12345678")
+	assert.Nil(t, err)
+	_, err = ks.Signer(address)
+	assert.Equal(t, ErrLocked, err)
+	assert.NotNil(t, ks.Unlock(address, "87654321", 0, 0))
+	assert.False(t, ks.IsUnlocked(address))
+
+	// unlocked for 2 signatures
+	assert.Nil(t, ks.Unlock(address, "12345678", 0, 2))
+	signer, err := ks.Signer(address)
+	assert.Nil(t, err)
+	assert.Equal(t, address, signer.GetAddress())
+	assert.Equal(t, AlgorithmSM2, signer.Algorithm())
+	batchSigner, ok := signer.(BatchSigner)
+	assert.True(t, ok)
+	digest, err := Digest(signer, []byte("data"))
+	assert.Nil(t, err)
+	_, err = signer.SignDigest(digest)
+	assert.Nil(t, err)
+	_, err = batchSigner.SignDigestBatch(digest)
+	assert.Nil(t, err)
+	_, err = signer.SignDigest(digest)
+	assert.Equal(t, ErrLocked, err)
+	assert.False(t, ks.IsUnlocked(address))
+
+	// unlocked for a duration
+	assert.Nil(t, ks.Unlock(address, "12345678", 50*time.Millisecond, 0))
+	_, err = signer.SignDigest(digest)
+	assert.Nil(t, err)
+	time.Sleep(100 * time.Millisecond)
+	_, err = signer.SignDigest(digest)
+	assert.Equal(t, ErrLocked, err)
+
+	assert.Nil(t, ks.Unlock(address, "12345678", 0, 0))
+	assert.True(t, ks.IsUnlocked(address))
+	ks.Lock(address)
+	_, err = signer.SignDigest(digest)
+	assert.Equal(t, ErrLocked, err)
+}
+
+func TestKeystore_Concurrent(t *testing.T) {
+	ks, dir := newTestKeystore(t)
+	defer os.RemoveAll(dir)
+	defer lightKDF()()
+	address, err := ks.NewAccount(ED25519KDF2, "12345678")
+	assert.Nil(t, err)
+	assert.Nil(t, ks.Unlock(address, "12345678", 0, 10))
+	signer, err := ks.Signer(address)
+	assert.Nil(t, err)
+
+	var (
+		wg     sync.WaitGroup
+		mutex  sync.Mutex
+		signed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := signer.SignDigest([]byte("data")); err == nil {
+				mutex.Lock()
+				signed++
+				mutex.Unlock()
+			}
+			_, _ = ks.Accounts()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10, signed)
+}
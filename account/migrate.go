@@ -0,0 +1,132 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperchain/gosdk/common"
+)
+
+// ReencryptAccountJson decrypt the account json by oldPassword and encrypt the same private key as acType by
+// newPassword, such as to migrate the accounts of ECDES and SMDES to ECSCRYPT and SMSCRYPT. acType must be of
+// the algorithm of the account. The new account json is checked by GenKeyFromAccountJson to decrypt to the same
+// key and address before it is returned. Unlike NewAccountJson, the secp256r1 accounts encrypted without kdf,
+// such as ECDESR1, are written with the algo of acType, so that they are decrypted as secp256r1 keys.
+func ReencryptAccountJson(accountJson, oldPassword, acType, newPassword string) (string, error) {
+	key, err := GenKeyFromAccountJson(accountJson, oldPassword)
+	if err != nil {
+		return "", err
+	}
+	newAccountJson, err := newAccountJsonFromKey(key, acType, newPassword)
+	if err != nil {
+		return "", err
+	}
+
+	migrated, err := GenKeyFromAccountJson(newAccountJson, newPassword)
+	if err != nil {
+		return "", errors.New("verify migrated account json failed: " + err.Error())
+	}
+	if err = sameKey(key, migrated); err != nil {
+		return "", err
+	}
+	return newAccountJson, nil
+}
+
+func sameKey(key, migrated Key) error {
+	if key.GetAddress() != migrated.GetAddress() {
+		return errors.New("verify migrated account json failed, address is inconsistent")
+	}
+	priv, err := rawPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	migratedPriv, err := rawPrivateKey(migrated)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(priv, migratedPriv) {
+		return errors.New("verify migrated account json failed, private key is inconsistent")
+	}
+	return nil
+}
+
+// MigrateResult is the result of migrating an account of keystore
+type MigrateResult struct {
+	Address common.Address
+	// Algo is the algo of account before migration
+	Algo string
+	// Err is the reason why the account is not migrated, whose file is kept as it was
+	Err error
+}
+
+// Migrate re-encrypt the account as ReencryptAccountJson and replace its file, the file is kept if the new
+// account json fails to be verified. An unlocked account stays unlocked.
+func (ks *Keystore) Migrate(address common.Address, oldPassword, acType, newPassword string) error {
+	accountJson, err := ks.Export(address)
+	if err != nil {
+		return err
+	}
+	newAccountJson, err := ReencryptAccountJson(accountJson, oldPassword, acType, newPassword)
+	if err != nil {
+		return err
+	}
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	// the account may be deleted or replaced in the meantime
+	current, err := ks.read(address)
+	if err != nil {
+		return err
+	}
+	if current != accountJson {
+		return errors.New("account file is modified during migration")
+	}
+	return writeFileAtomic(ks.path(address), []byte(newAccountJson))
+}
+
+// MigrateAll migrate the accounts whose algo is one of algos as Migrate, or the accounts of the algorithm of
+// acType but not of acType if algos is empty, where ECDSA K1 and R1 are different algorithms. The accounts are
+// migrated one by one, a failed one does not stop the others, the results are returned in the order of Accounts.
+func (ks *Keystore) MigrateAll(algos []string, oldPassword, acType, newPassword string) ([]MigrateResult, error) {
+	addresses, err := ks.Accounts()
+	if err != nil {
+		return nil, err
+	}
+	var results []MigrateResult
+	for _, address := range addresses {
+		result := MigrateResult{Address: address}
+		accountJson, err := ks.Export(address)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		account := new(accountJSON)
+		if err = json.Unmarshal([]byte(accountJson), account); err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		result.Algo = account.Algo
+		if !migrateAlgo(account.Algo, algos, acType) {
+			continue
+		}
+		result.Err = ks.Migrate(address, oldPassword, acType, newPassword)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func migrateAlgo(algo string, algos []string, acType string) bool {
+	if len(algos) == 0 {
+		algorithm := acTypeAlgorithm(acType)
+		return algorithm != "" && acTypeAlgorithm(algo) == algorithm && algo != acType
+	}
+	for _, a := range algos {
+		if a == algo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package account
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReencryptAccountJson(t *testing.T) {
+	defer lightKDF()()
+
+	old, err := NewAccountJson(SMDES, "123")
+	assert.Nil(t, err)
+	migrated, err := ReencryptAccountJson(old, "123", SMSCRYPT, "456")
+	assert.Nil(t, err)
+
+	oldAccount, newAccount := new(accountJSON), new(accountJSON)
+	assert.Nil(t, json.Unmarshal([]byte(old), oldAccount))
+	assert.Nil(t, json.Unmarshal([]byte(migrated), newAccount))
+	assert.Equal(t, SMSCRYPT, newAccount.Algo)
+	assert.Equal(t, oldAccount.Address, newAccount.Address)
+	assert.Equal(t, oldAccount.PublicKey, newAccount.PublicKey)
+	_, err = GenKeyFromAccountJson(migrated, "456")
+	assert.Nil(t, err)
+	_, err = GenKeyFromAccountJson(migrated, "123")
+	assert.NotNil(t, err)
+
+	// back to a legacy algo without password
+	raw, err := ReencryptAccountJson(migrated, "456", SMRAW, "")
+	assert.Nil(t, err)
+	rawAccount := new(accountJSON)
+	assert.Nil(t, json.Unmarshal([]byte(raw), rawAccount))
+	assert.Equal(t, SMRAW, rawAccount.Algo)
+	assert.Equal(t, oldAccount.Address, rawAccount.Address)
+
+	_, err = ReencryptAccountJson(migrated, "123", SMSCRYPT, "456")
+	assert.NotNil(t, err)
+	_, err = ReencryptAccountJson(old, "123", ECSCRYPT, "456")
+	assert.NotNil(t, err)
+	_, err = ReencryptAccountJson(old, "123", "0x1f", "456")
+	assert.NotNil(t, err)
+}
+
+func TestReencryptAccountJson_ECDSA(t *testing.T) {
+	defer lightKDF()()
+
+	old, err := NewAccountJson(ECDES, "123")
+	assert.Nil(t, err)
+	migrated, err := ReencryptAccountJson(old, "123", ECSCRYPT, "456")
+	assert.Nil(t, err)
+
+	oldAccount, newAccount := new(accountJSON), new(accountJSON)
+	assert.Nil(t, json.Unmarshal([]byte(old), oldAccount))
+	assert.Nil(t, json.Unmarshal([]byte(migrated), newAccount))
+	assert.Equal(t, ECSCRYPT, newAccount.Algo)
+	assert.Equal(t, oldAccount.Address, newAccount.Address)
+	key, err := GenKeyFromAccountJson(migrated, "456")
+	assert.Nil(t, err)
+	assert.Equal(t, AlgorithmECDSAK1, key.Algorithm())
+
+	// K1 keys can not be encrypted as R1 accounts
+	_, err = ReencryptAccountJson(old, "123", ECSCRYPTR1, "456")
+	assert.NotNil(t, err)
+}
+
+func TestMigrateAlgo(t *testing.T) {
+	assert.True(t, migrateAlgo(ECDES, nil, ECSCRYPT))
+	assert.True(t, migrateAlgo(ECKDF2R1, nil, ECSCRYPTR1))
+	assert.True(t, migrateAlgo(SMDES, nil, SMSCRYPT))
+	// ECDSA K1 and R1 are different algorithms
+	assert.False(t, migrateAlgo(ECKDF2R1, nil, ECSCRYPT))
+	assert.False(t, migrateAlgo(ECDES, nil, ECSCRYPTR1))
+	assert.False(t, migrateAlgo(ECSCRYPT, nil, ECSCRYPT))
+	assert.False(t, migrateAlgo(SMDES, nil, ECSCRYPT))
+	assert.False(t, migrateAlgo(SMDES, nil, PKI))
+
+	assert.True(t, migrateAlgo(ED25519DES, []string{SMDES, ED25519DES}, SMSCRYPT))
+	assert.False(t, migrateAlgo(SMKDF2, []string{SMDES}, SMSCRYPT))
+}
+
+func TestKeystore_MigrateAll(t *testing.T) {
+	defer lightKDF()()
+	ks, dir := newTestKeystore(t)
+	defer os.RemoveAll(dir)
+
+	sm2, err := ks.NewAccount(SMDES, "123")
+	assert.Nil(t, err)
+	ed, err := ks.NewAccount(ED25519DES, "123")
+	assert.Nil(t, err)
+	assert.Nil(t, ks.Unlock(sm2, "123", 0, 0))
+
+	// only the accounts of SM2 are migrated to SMSCRYPT
+	results, err := ks.MigrateAll(nil, "123", SMSCRYPT, "456")
+	assert.Nil(t, err)
+	assert.Equal(t, []MigrateResult{{Address: sm2, Algo: SMDES}}, results)
+	exported, err := ks.Export(sm2)
+	assert.Nil(t, err)
+	account := new(accountJSON)
+	assert.Nil(t, json.Unmarshal([]byte(exported), account))
+	assert.Equal(t, SMSCRYPT, account.Algo)
+	assert.Nil(t, ks.Unlock(sm2, "456", 0, 0))
+	assert.True(t, ks.IsUnlocked(sm2))
+
+	// the file is kept if the account fails to migrate
+	before, err := ks.Export(ed)
+	assert.Nil(t, err)
+	results, err = ks.MigrateAll([]string{ED25519DES}, "123", SMSCRYPT, "456")
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, ed, results[0].Address)
+	assert.NotNil(t, results[0].Err)
+	after, err := ks.Export(ed)
+	assert.Nil(t, err)
+	assert.Equal(t, before, after)
+
+	results, err = ks.MigrateAll([]string{ED25519DES}, "123", ED25519SCRYPT, "456")
+	assert.Nil(t, err)
+	assert.Equal(t, []MigrateResult{{Address: ed, Algo: ED25519DES}}, results)
+	_, err = GenKeyFromAccountJson(mustExport(t, ks, ed), "456")
+	assert.Nil(t, err)
+}
+
+func TestReencryptAccountJson_R1(t *testing.T) {
+	defer lightKDF()()
+
+	old, err := NewAccountJson(ECSCRYPTR1, "123")
+	assert.Nil(t, err)
+	oldAccount := new(accountJSON)
+	assert.Nil(t, json.Unmarshal([]byte(old), oldAccount))
+
+	// the R1 accounts encrypted without kdf are written with the R1 algo to decrypt as R1 keys
+	for _, acType := range []string{ECDESR1, ECRAWR1, ECAESR1, EC3DESR1, ECKDF2R1} {
+		migrated, err := ReencryptAccountJson(old, "123", acType, "456")
+		assert.Nil(t, err, acType)
+		newAccount := new(accountJSON)
+		assert.Nil(t, json.Unmarshal([]byte(migrated), newAccount))
+		assert.Equal(t, acType, newAccount.Algo)
+		assert.Equal(t, oldAccount.Address, newAccount.Address)
+		key, err := GenKeyFromAccountJson(migrated, "456")
+		assert.Nil(t, err, acType)
+		assert.Equal(t, AlgorithmECDSAR1, key.Algorithm())
+	}
+}
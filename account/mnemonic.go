@@ -0,0 +1,30 @@
+package account
+
+import (
+	"github.com/tyler-smith/go-bip39"
+)
+
+// NewMnemonic generate a BIP39 mnemonic of english words from bitSize bits of random entropy, bitSize is
+// a multiple of 32 in [128, 256], so the mnemonic has 12 to 24 words
+func NewMnemonic(bitSize int) (string, error) {
+	entropy, err := bip39.NewEntropy(bitSize)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic check the words and the checksum of a BIP39 mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	_, err := bip39.EntropyFromMnemonic(mnemonic)
+	return err
+}
+
+// MnemonicToSeed return the 64 bytes BIP39 seed of the mnemonic protected by passphrase, which may be empty.
+// The mnemonic is validated first.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return bip39.NewSeed(mnemonic, passphrase), nil
+}
@@ -0,0 +1,217 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperchain/gosdk/common"
+)
+
+// DefaultRemoteSignerTimeout is the timeout of requests of RemoteSigner if no http client is given
+const DefaultRemoteSignerTimeout = 10 * time.Second
+
+// The protocol of signing daemon:
+//
+//	GET  {endpoint}/keys/{address}       -> {"address": "0x..", "algorithm": "SM2", "publicKey": "0x.."}
+//	POST {endpoint}/keys/{address}/sign  {"digest": "0x..", "batch": false} -> {"signature": "0x.."}
+//
+// a failed request responds a non 2xx status with {"error": "message"}.
+type remoteKeyInfo struct {
+	Address   string    `json:"address"`
+	Algorithm Algorithm `json:"algorithm"`
+	PublicKey string    `json:"publicKey"`
+}
+
+type remoteSignRequest struct {
+	Digest string `json:"digest"`
+	Batch  bool   `json:"batch,omitempty"`
+}
+
+type remoteSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+type remoteError struct {
+	Error string `json:"error"`
+}
+
+// RemoteSigner is a reference Signer which signs by a local signing daemon over HTTP,
+// the private key stays in the daemon, which may keep it in an HSM
+type RemoteSigner struct {
+	endpoint  string
+	client    *http.Client
+	address   common.Address
+	algorithm Algorithm
+	publicKey []byte
+}
+
+// NewRemoteSigner return a RemoteSigner of the account of address kept by the daemon at endpoint,
+// the algorithm and the public key of account are fetched from the daemon. A client with
+// DefaultRemoteSignerTimeout is used if client is nil.
+func NewRemoteSigner(endpoint, address string, client *http.Client) (*RemoteSigner, error) {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultRemoteSignerTimeout}
+	}
+	s := &RemoteSigner{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+		address:  common.HexToAddress(address),
+	}
+	var info remoteKeyInfo
+	if err := s.do(http.MethodGet, s.keyURL(), nil, &info); err != nil {
+		return nil, err
+	}
+	if common.HexToAddress(info.Address) != s.address {
+		return nil, fmt.Errorf("signing daemon returned key of %s instead of %s", info.Address, s.address.Hex())
+	}
+	s.algorithm = info.Algorithm
+	s.publicKey = common.FromHex(info.PublicKey)
+	return s, nil
+}
+
+// GetAddress return the address of account
+func (s *RemoteSigner) GetAddress() common.Address {
+	return s.address
+}
+
+// Algorithm return the signature algorithm of account
+func (s *RemoteSigner) Algorithm() Algorithm {
+	return s.algorithm
+}
+
+// PublicBytes return the public key of account
+func (s *RemoteSigner) PublicBytes() ([]byte, error) {
+	return s.publicKey, nil
+}
+
+// SignDigest sign the digest by the daemon
+func (s *RemoteSigner) SignDigest(digest []byte) ([]byte, error) {
+	return s.sign(digest, false)
+}
+
+// SignDigestBatch sign the digest with batch flag by the daemon
+func (s *RemoteSigner) SignDigestBatch(digest []byte) ([]byte, error) {
+	return s.sign(digest, true)
+}
+
+func (s *RemoteSigner) sign(digest []byte, batch bool) ([]byte, error) {
+	var resp remoteSignResponse
+	req := &remoteSignRequest{Digest: common.ToHex(digest), Batch: batch}
+	if err := s.do(http.MethodPost, s.keyURL()+"/sign", req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Signature == "" {
+		return nil, errors.New("signing daemon returned empty signature")
+	}
+	return common.FromHex(resp.Signature), nil
+}
+
+func (s *RemoteSigner) keyURL() string {
+	return s.endpoint + "/keys/" + s.address.Hex()
+}
+
+func (s *RemoteSigner) do(method, url string, body, result interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request signing daemon error: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response of signing daemon error: %v", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		var re remoteError
+		if json.Unmarshal(data, &re) == nil && re.Error != "" {
+			return fmt.Errorf("signing daemon responded %d: %s", resp.StatusCode, re.Error)
+		}
+		return fmt.Errorf("signing daemon responded %d", resp.StatusCode)
+	}
+	return json.Unmarshal(data, result)
+}
+
+// NewSignerHandler return a http handler serving the protocol of RemoteSigner by signers, it is a reference
+// of signing daemon and can be used to serve the keys in process for test
+func NewSignerHandler(signers ...Signer) http.Handler {
+	keys := make(map[common.Address]Signer, len(signers))
+	for _, signer := range signers {
+		keys[signer.GetAddress()] = signer
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/keys/")
+		if path == r.URL.Path {
+			writeRemoteError(w, http.StatusNotFound, "not found")
+			return
+		}
+		address, sign := strings.TrimSuffix(path, "/sign"), strings.HasSuffix(path, "/sign")
+		signer, ok := keys[common.HexToAddress(address)]
+		if !ok {
+			writeRemoteError(w, http.StatusNotFound, "unknown key "+address)
+			return
+		}
+		switch {
+		case !sign && r.Method == http.MethodGet:
+			pub, err := signer.PublicBytes()
+			if err != nil {
+				writeRemoteError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			writeRemoteResult(w, &remoteKeyInfo{
+				Address:   signer.GetAddress().Hex(),
+				Algorithm: signer.Algorithm(),
+				PublicKey: common.ToHex(pub),
+			})
+		case sign && r.Method == http.MethodPost:
+			var req remoteSignRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeRemoteError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			var (
+				sig []byte
+				err error
+			)
+			if bs, ok := signer.(BatchSigner); req.Batch && ok {
+				sig, err = bs.SignDigestBatch(common.FromHex(req.Digest))
+			} else {
+				sig, err = signer.SignDigest(common.FromHex(req.Digest))
+			}
+			if err != nil {
+				writeRemoteError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			writeRemoteResult(w, &remoteSignResponse{Signature: common.ToHex(sig)})
+		default:
+			writeRemoteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})
+}
+
+func writeRemoteResult(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+func writeRemoteError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&remoteError{Error: message})
+}
@@ -0,0 +1,91 @@
+package account
+
+import (
+	"fmt"
+
+	"github.com/hyperchain/gosdk/common"
+	gm "github.com/ultramesh/crypto-gm"
+	"github.com/ultramesh/crypto-standard/asym"
+	"github.com/ultramesh/crypto-standard/ed25519"
+	"github.com/ultramesh/crypto-standard/hash"
+)
+
+// Algorithm is the signature algorithm of an account, which decides how the signed data is hashed
+// and how the signature is encoded
+type Algorithm string
+
+const (
+	// AlgorithmECDSAK1 is ECDSA on secp256k1, the signature is recoverable
+	AlgorithmECDSAK1 Algorithm = "ECDSA_K1"
+	// AlgorithmECDSAR1 is ECDSA on secp256r1
+	AlgorithmECDSAR1 Algorithm = "ECDSA_R1"
+	// AlgorithmSM2 is SM2
+	AlgorithmSM2 Algorithm = "SM2"
+	// AlgorithmED25519 is ED25519
+	AlgorithmED25519 Algorithm = "ED25519"
+	// AlgorithmPKIECDSA is ECDSA of PKI account, the public key is in its certificate
+	AlgorithmPKIECDSA Algorithm = "PKI_ECDSA"
+	// AlgorithmPKISM2 is SM2 of PKI account, the public key is in its certificate
+	AlgorithmPKISM2 Algorithm = "PKI_SM2"
+)
+
+// Signer signs for an account, it is accepted everywhere a key is, such as Transaction.Sign,
+// so the private key can be kept out of process, such as in an HSM
+type Signer interface {
+	// GetAddress return the address of account
+	GetAddress() common.Address
+	// Algorithm return the signature algorithm of account
+	Algorithm() Algorithm
+	// PublicBytes return the uncompressed public key of account
+	PublicBytes() ([]byte, error)
+	// SignDigest sign the digest, which is hashed according to the algorithm already
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// BatchSigner is implemented by the Signer supporting the signature verified in batch,
+// it is used by SignWithBatchFlag of SM2 accounts
+type BatchSigner interface {
+	Signer
+	// SignDigestBatch sign the digest with batch flag
+	SignDigestBatch(digest []byte) ([]byte, error)
+}
+
+// NewSigner return the Signer of key, a Signer such as Key is returned as it is, and the private keys
+// of the crypto libraries, which were passed as interface{} by older code, are wrapped as their Key
+func NewSigner(key interface{}) (Signer, error) {
+	switch k := key.(type) {
+	case Signer:
+		return k, nil
+	case *asym.ECDSAPrivateKey:
+		return &ECDSAKey{k}, nil
+	case *gm.SM2PrivateKey:
+		return &SM2Key{k}, nil
+	case *ed25519.EDDSAPrivateKey:
+		return &ED25519Key{k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// Digest hash the data signed by the signer according to its algorithm, the result is passed to Signer.SignDigest
+func Digest(s Signer, data []byte) ([]byte, error) {
+	switch s.Algorithm() {
+	case AlgorithmECDSAK1, AlgorithmECDSAR1, AlgorithmPKIECDSA:
+		return hash.NewHasher(hash.KECCAK_256).Hash(data)
+	case AlgorithmSM2, AlgorithmPKISM2:
+		pubBytes, err := s.PublicBytes()
+		if err != nil {
+			return nil, err
+		}
+		pub, err := new(gm.SM2PublicKey).FromBytes(pubBytes, nil)
+		if err != nil {
+			return nil, err
+		}
+		return gm.HashBeforeSM2(pub, data), nil
+	case AlgorithmED25519:
+		// ed25519 hashes the data itself
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", s.Algorithm())
+	}
+}
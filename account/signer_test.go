@@ -0,0 +1,92 @@
+package account
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSigner(t *testing.T) {
+	k1, err := NewAccountFromPriv("a1fd6ed6225e76aac3884b5420c8cdbb4fde1db01e9ef773415b8f2b5a9b77d4")
+	assert.Nil(t, err)
+	sm2, err := NewAccountSm2FromPriv("a1fd6ed6225e76aac3884b5420c8cdbb4fde1db01e9ef773415b8f2b5a9b77d4")
+	assert.Nil(t, err)
+	ed, err := GenKeyFromAccountJson(mustAccountJSON(t, ED25519RAW), "")
+	assert.Nil(t, err)
+
+	// the private keys of the crypto libraries are wrapped as their keys
+	for key, expect := range map[interface{}]Key{
+		k1.ECDSAPrivateKey:               k1,
+		sm2.SM2PrivateKey:                sm2,
+		ed.(*ED25519Key).EDDSAPrivateKey: ed,
+	} {
+		signer, err := NewSigner(key)
+		assert.Nil(t, err)
+		assert.Equal(t, expect.Algorithm(), signer.Algorithm())
+		assert.Equal(t, expect.GetAddress(), signer.GetAddress())
+		pub, _ := expect.PublicBytes()
+		signerPub, err := signer.PublicBytes()
+		assert.Nil(t, err)
+		assert.Equal(t, pub, signerPub)
+
+		digest, err := Digest(signer, []byte("data"))
+		assert.Nil(t, err)
+		sig, err := signer.SignDigest(digest)
+		assert.Nil(t, err)
+		valid, err := expect.Verify(digest, sig)
+		assert.Nil(t, err)
+		assert.True(t, valid)
+
+		// a signer is returned as it is
+		same, err := NewSigner(signer)
+		assert.Nil(t, err)
+		assert.True(t, same == signer)
+	}
+	_, ok := interface{}(&SM2Key{}).(BatchSigner)
+	assert.True(t, ok)
+
+	_, err = NewSigner("key")
+	assert.NotNil(t, err)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := NewAccountSm2FromPriv("a1fd6ed6225e76aac3884b5420c8cdbb4fde1db01e9ef773415b8f2b5a9b77d4")
+	assert.Nil(t, err)
+	local, err := NewSigner(key)
+	assert.Nil(t, err)
+	srv := httptest.NewServer(NewSignerHandler(local))
+	defer srv.Close()
+
+	remote, err := NewRemoteSigner(srv.URL+"/", key.GetAddress().Hex(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, key.GetAddress(), remote.GetAddress())
+	assert.Equal(t, AlgorithmSM2, remote.Algorithm())
+	pub, _ := key.PublicBytes()
+	remotePub, err := remote.PublicBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, pub, remotePub)
+
+	digest, err := Digest(remote, []byte("data"))
+	assert.Nil(t, err)
+	sig, err := remote.SignDigest(digest)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, sig)
+	sig, err = remote.SignDigestBatch(digest)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, sig)
+
+	_, err = NewRemoteSigner(srv.URL, "0x6201cb0448964ac597faf6fdf1f472edf2a22b89", nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown key")
+	_, err = NewRemoteSigner("http://127.0.0.1:1", key.GetAddress().Hex(), nil)
+	assert.NotNil(t, err)
+}
+
+func mustAccountJSON(t *testing.T, acType string) string {
+	accountJSON, err := NewAccountJson(acType, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return accountJSON
+}
@@ -0,0 +1,95 @@
+// Command accountmigrate re-encrypts account json files with another algo and password, keeping the address
+// and the private key, such as to migrate the accounts of ECDES and SMDES to ECSCRYPT and SMSCRYPT.
+// A file is replaced only after the new account json is verified to decrypt to the same key.
+//
+//	accountmigrate -in account.json -type 0x06 -old-password 123 -new-password 456 > new.json
+//	accountmigrate -keystore ../conf/keystore -from 0x12 -type 0x17
+//
+// The passwords default to the environment variables GOSDK_OLD_PASSWORD and GOSDK_NEW_PASSWORD,
+// so they need not appear in the command line.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hyperchain/gosdk/account"
+)
+
+func main() {
+	var (
+		in          = flag.String("in", "", "account json file to migrate, the new account json is written to -out")
+		out         = flag.String("out", "", "file of the new account json, stdout if it is empty")
+		keystore    = flag.String("keystore", "", "keystore directory whose account files are migrated in place")
+		from        = flag.String("from", "", "comma separated algos of the migrated accounts in keystore, such as 0x02,0x12, all accounts of the algorithm of -type if it is empty")
+		acType      = flag.String("type", account.ECSCRYPT, "algo of the new account json, the same algorithm as the account")
+		oldPassword = flag.String("old-password", os.Getenv("GOSDK_OLD_PASSWORD"), "password of the account")
+		newPassword = flag.String("new-password", os.Getenv("GOSDK_NEW_PASSWORD"), "password of the new account json")
+	)
+	flag.Parse()
+
+	switch {
+	case *in != "" && *keystore == "":
+		if err := migrateFile(*in, *out, *oldPassword, *acType, *newPassword); err != nil {
+			exit(err)
+		}
+	case *keystore != "" && *in == "":
+		var algos []string
+		if *from != "" {
+			algos = strings.Split(*from, ",")
+		}
+		if !migrateKeystore(*keystore, algos, *oldPassword, *acType, *newPassword) {
+			os.Exit(1)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func migrateFile(in, out, oldPassword, acType, newPassword string) error {
+	data, err := ioutil.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	accountJson, err := account.ReencryptAccountJson(string(data), oldPassword, acType, newPassword)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		fmt.Println(accountJson)
+		return nil
+	}
+	return ioutil.WriteFile(out, []byte(accountJson), 0600)
+}
+
+// migrateKeystore migrate the accounts of dir and print the results, it returns false if any account fails
+func migrateKeystore(dir string, algos []string, oldPassword, acType, newPassword string) bool {
+	ks, err := account.NewKeystore(dir)
+	if err != nil {
+		exit(err)
+	}
+	results, err := ks.MigrateAll(algos, oldPassword, acType, newPassword)
+	if err != nil {
+		exit(err)
+	}
+	migrated := 0
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Printf("%s %s failed: %v\n", result.Address.Hex(), result.Algo, result.Err)
+			continue
+		}
+		migrated++
+		fmt.Printf("%s %s -> %s\n", result.Address.Hex(), result.Algo, acType)
+	}
+	fmt.Fprintf(os.Stderr, "%d of %d accounts migrated\n", migrated, len(results))
+	return migrated == len(results)
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,127 @@
+// Command loadgen benchmarks a chain by sending pre-signed transfer or invoke transactions,
+// and prints the throughput, latency percentiles and errors as text or json.
+//
+//	loadgen -conf ../conf -n 10000 -accounts 100 -rate 2000 -concurrency 64 -confirm
+//	loadgen -conf ../conf -n 10000 -to 0x<contract> -payload 0x<abi encoded input> -json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/hyperchain/gosdk/account"
+	"github.com/hyperchain/gosdk/common"
+	"github.com/hyperchain/gosdk/loadgen"
+	"github.com/hyperchain/gosdk/rpc"
+)
+
+func main() {
+	var (
+		conf        = flag.String("conf", common.DefaultConfRootPath, "directory of hpc.toml")
+		accounts    = flag.Int("accounts", 100, "number of generated accounts signing the transactions")
+		sm2         = flag.Bool("sm2", false, "generate SM2 accounts instead of ECDSA ones")
+		n           = flag.Int("n", 1000, "number of transactions")
+		to          = flag.String("to", "0x6201cb0448964ac597faf6fdf1f472edf2a22b89", "receiver of transfers or address of the invoked contract")
+		payload     = flag.String("payload", "", "hex payload invoking the contract, transfers are sent if it is empty")
+		vmType      = flag.String("vm", string(rpc.EVM), "vm type of the invoked contract")
+		rate        = flag.Int("rate", 0, "transactions sent per second, 0 means as fast as concurrency allows")
+		concurrency = flag.Int("concurrency", loadgen.DefaultConcurrency, "transactions sent at the same time")
+		confirm     = flag.Bool("confirm", false, "wait for receipts to measure confirmation latency")
+		websocket   = flag.Bool("websocket", false, "wait for receipts by websocket block events instead of polling")
+		timeout     = flag.Duration("timeout", 0, "stop sending after the duration, 0 means no limit")
+		asJSON      = flag.Bool("json", false, "print the report in json")
+	)
+	flag.Parse()
+
+	rp := rpc.NewRPCWithPath(*conf)
+	defer rp.Close()
+	if *websocket {
+		waiter := rp.NewReceiptWaiter()
+		defer waiter.Close()
+		rp.ReceiptWaiter(waiter)
+	}
+
+	signers, err := generateSigners(*accounts, *sm2)
+	if err != nil {
+		exit(err)
+	}
+	// sending is stopped by -timeout or the first interrupt, the confirmation is aborted by the second one
+	confirmCtx, cancelConfirm := context.WithCancel(context.Background())
+	defer cancelConfirm()
+	gen, err := loadgen.New(rp.WithContext(confirmCtx), loadgen.Config{
+		Signers:      signers,
+		Transactions: *n,
+		To:           *to,
+		Payload:      common.FromHex(*payload),
+		VMType:       rpc.VMType(*vmType),
+		Rate:         *rate,
+		Concurrency:  *concurrency,
+		Confirm:      *confirm,
+	})
+	if err != nil {
+		exit(err)
+	}
+	fmt.Fprintf(os.Stderr, "signing %d transactions by %d accounts\n", *n, len(signers))
+	if err := gen.Presign(); err != nil {
+		exit(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if *timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+		<-interrupt
+		cancelConfirm()
+	}()
+
+	fmt.Fprintf(os.Stderr, "sending to %d nodes\n", len(rp.NodeURLs()))
+	start := time.Now()
+	report, err := gen.Run(ctx)
+	if err != nil {
+		exit(err)
+	}
+	fmt.Fprintf(os.Stderr, "done in %v\n", time.Since(start))
+	if *asJSON {
+		data, err := report.JSON()
+		if err != nil {
+			exit(err)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Print(report.String())
+}
+
+func generateSigners(n int, sm2 bool) ([]account.Signer, error) {
+	acType := account.ECRAW
+	if sm2 {
+		acType = account.SMRAW
+	}
+	signers := make([]account.Signer, n)
+	for i := range signers {
+		accountJSON, err := account.NewAccountJson(acType, "")
+		if err != nil {
+			return nil, err
+		}
+		if signers[i], err = account.GenKeyFromAccountJson(accountJSON, ""); err != nil {
+			return nil, err
+		}
+	}
+	return signers, nil
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
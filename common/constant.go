@@ -11,14 +11,21 @@ const (
 )
 
 const (
-	JSONRPCNodes = "jsonRPC.nodes"
-	JSONRPCPorts = "jsonRPC.ports"
+	JSONRPCNodes    = "jsonRPC.nodes"
+	JSONRPCPorts    = "jsonRPC.ports"
+	JSONRPCSelector = "jsonRPC.selector"
+	JSONRPCWeights  = "jsonRPC.weights"
 )
 
 const (
 	WebSocketPorts = "webSocket.ports"
 )
 
+const (
+	HealthFailureThreshold = "health.failureThreshold"
+	HealthProbeInterval    = "health.probeInterval"
+)
+
 const (
 	PollingResendTime            = "polling.resendTime"
 	PollingFirstPollingInterval  = "polling.firstPollingInterval"
@@ -1,6 +1,8 @@
 package hvm
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"github.com/hyperchain/gosdk/common"
 	"strings"
@@ -96,6 +98,55 @@ func (abi Abi) GetMethodAbi(methodName string) (*BeanAbi, error) {
 	return nil, errors.New("can not find method bean " + methodName)
 }
 
+// VMType return "HVM", the vm type of contracts described by abi
+func (abi Abi) VMType() string {
+	return "HVM"
+}
+
+// EncodeInput return the payload invoking the invoke bean or method bean named method with params
+func (abi Abi) EncodeInput(method string, params ...interface{}) ([]byte, error) {
+	beanAbi, err := abi.getInvokeOrMethodAbi(method)
+	if err != nil {
+		return nil, err
+	}
+	return GenPayload(beanAbi, params...)
+}
+
+// DecodeOutput decode the return value of the bean named method. A String output is returned as string,
+// other outputs are decoded from json as json.Number, bool, string, []interface{} or map[string]interface{},
+// or kept as string if they are not json. No value is returned if the output is Void.
+func (abi Abi) DecodeOutput(method string, ret []byte) ([]interface{}, error) {
+	beanAbi, err := abi.getInvokeOrMethodAbi(method)
+	if err != nil {
+		return nil, err
+	}
+	if beanAbi.Output.EntryType == Void || beanAbi.Output.EntryType == "" && len(ret) == 0 {
+		return []interface{}{}, nil
+	}
+	if beanAbi.Output.EntryType == String {
+		var str string
+		if err := json.Unmarshal(ret, &str); err != nil {
+			str = string(ret)
+		}
+		return []interface{}{str}, nil
+	}
+	var value interface{}
+	d := json.NewDecoder(bytes.NewReader(ret))
+	d.UseNumber()
+	if err := d.Decode(&value); err != nil || d.More() {
+		value = string(ret)
+	}
+	return []interface{}{value}, nil
+}
+
+// getInvokeOrMethodAbi return the invoke bean named name, or the method bean if there is no such invoke bean
+func (abi Abi) getInvokeOrMethodAbi(name string) (*BeanAbi, error) {
+	if beanAbi, err := abi.GetBeanAbi(name); err == nil {
+		return beanAbi, nil
+	}
+	return abi.GetMethodAbi(name)
+}
+
 func (beanAbi *BeanAbi) resolveStruct(input Entry, param interface{}) string {
 	result := "{"
 	s, err := beanAbi.getStruct(input.StructName)
@@ -29,3 +29,33 @@ func TestAbi_GetMethodAbi(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(methodAbi4.Inputs))
 }
+
+func TestAbi_DecodeOutput(t *testing.T) {
+	abiJson, err := common.ReadFileAsString("../hvmtestfile/methodInvoke/hvm.abi")
+	assert.Nil(t, err)
+	abi, err := GenAbi(abiJson)
+	assert.Nil(t, err)
+	assert.Equal(t, "HVM", abi.VMType())
+
+	values, err := abi.DecodeOutput("cn.hyperchain.invoke.ShareInvoke", []byte("true"))
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{true}, values)
+
+	values, err = abi.DecodeOutput("Hello()", []byte("hello"))
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"hello"}, values)
+	values, err = abi.DecodeOutput("Hello()", []byte(`"123"`))
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"123"}, values)
+
+	values, err = abi.DecodeOutput("displayMan", nil)
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+
+	_, err = abi.DecodeOutput("notExist", nil)
+	assert.NotNil(t, err)
+
+	payload, err := abi.EncodeInput("Hello()")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, payload)
+}
@@ -0,0 +1,221 @@
+// Package loadgen generates load on a chain by pre-signed transactions sent with FastInvokeContract,
+// and reports the throughput, the latencies of sending and confirmation and the errors.
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperchain/gosdk/account"
+	"github.com/hyperchain/gosdk/rpc"
+)
+
+// DefaultConcurrency is the default number of transactions sent at the same time
+const DefaultConcurrency = 16
+
+// Config is the config of Generator
+type Config struct {
+	// Signers sign the transactions in turn, so the transactions are spread across accounts
+	Signers []account.Signer
+	// Transactions is the number of transactions to send
+	Transactions int
+	// To is the receiver of transfers or the address of the invoked contract
+	To string
+	// Payload is the payload of contract invocation, the transactions are transfers if it is empty
+	Payload []byte
+	// VMType is the vm type of the invoked contract, EVM by default
+	VMType rpc.VMType
+	// Rate is the number of transactions sent per second, 0 means as fast as Concurrency allows
+	Rate int
+	// Concurrency is the number of transactions sent at the same time, DefaultConcurrency by default
+	Concurrency int
+	// Confirm waits for the receipts of the sent transactions to measure the confirmation latency,
+	// the receipts are waited by RPC.WaitReceipt, so by the ReceiptWaiter and the context of rpc
+	Confirm bool
+	// ConfirmConcurrency is the number of receipts waited at the same time, Concurrency by default
+	ConfirmConcurrency int
+}
+
+// Generator sends the pre-signed transactions to all nodes of rpc in turn
+type Generator struct {
+	rpc    *rpc.RPC
+	config Config
+	urls   []string
+	bodies [][]byte
+}
+
+// result is the result of a transaction
+type result struct {
+	// hash is the hash returned by the node, which is waited for the receipt
+	hash         string
+	requestTime  time.Time
+	responseTime time.Time
+	confirmTime  time.Time
+	sendErr      *ErrorCount
+	confirmErr   *ErrorCount
+}
+
+// New return a Generator sending transactions by rpc
+func New(rp *rpc.RPC, config Config) (*Generator, error) {
+	if len(config.Signers) == 0 {
+		return nil, errors.New("no signer is given")
+	}
+	if config.Transactions <= 0 {
+		return nil, errors.New("the number of transactions should be positive")
+	}
+	if config.To == "" {
+		return nil, errors.New("the receiver or contract address is not given")
+	}
+	if config.Rate < 0 {
+		return nil, errors.New("the rate should not be negative")
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.ConfirmConcurrency <= 0 {
+		config.ConfirmConcurrency = config.Concurrency
+	}
+	if config.VMType == "" {
+		config.VMType = rpc.EVM
+	}
+	urls := rp.NodeURLs()
+	if len(urls) == 0 {
+		return nil, errors.New("no node is configured")
+	}
+	return &Generator{
+		rpc:    rp,
+		config: config,
+		urls:   urls,
+	}, nil
+}
+
+// Presign builds and signs all transactions, so the signing is not measured by Run.
+// It is called by Run if it is not called before.
+func (g *Generator) Presign() error {
+	bodies := make([][]byte, g.config.Transactions)
+	for i := range bodies {
+		signer := g.config.Signers[i%len(g.config.Signers)]
+		tx := rpc.NewTransaction(signer.GetAddress().Hex())
+		if len(g.config.Payload) == 0 {
+			tx = tx.Transfer(g.config.To, 1)
+		} else {
+			tx = tx.Invoke(g.config.To, g.config.Payload).VMType(g.config.VMType)
+		}
+		tx.Sign(signer)
+		body, err := g.rpc.TxRequestBody(tx)
+		if err != nil {
+			return fmt.Errorf("build transaction %d error: %v", i, err)
+		}
+		bodies[i] = body
+	}
+	g.bodies = bodies
+	return nil
+}
+
+// Run sends the transactions at the configured rate and concurrency and return the report,
+// the transactions not sent yet are skipped when ctx is done. ctx does not stop the confirmation,
+// the receipts of the sent transactions are still waited with the context of rpc given to New.
+func (g *Generator) Run(ctx context.Context) (*Report, error) {
+	if g.bodies == nil {
+		if err := g.Presign(); err != nil {
+			return nil, err
+		}
+	}
+	results := make([]*result, len(g.bodies))
+	jobs := make(chan int)
+	confirms := make(chan int, len(g.bodies))
+
+	var confirmWg sync.WaitGroup
+	if g.config.Confirm {
+		confirmWg.Add(g.config.ConfirmConcurrency)
+		for i := 0; i < g.config.ConfirmConcurrency; i++ {
+			go func() {
+				defer confirmWg.Done()
+				for index := range confirms {
+					g.confirm(results[index])
+				}
+			}()
+		}
+	}
+
+	var sendWg sync.WaitGroup
+	sendWg.Add(g.config.Concurrency)
+	for i := 0; i < g.config.Concurrency; i++ {
+		go func() {
+			defer sendWg.Done()
+			for index := range jobs {
+				res := g.send(g.bodies[index], g.urls[index%len(g.urls)])
+				results[index] = res
+				if g.config.Confirm && res.sendErr == nil {
+					confirms <- index
+				}
+			}
+		}()
+	}
+
+	start := time.Now()
+	g.dispatch(ctx, jobs)
+	sendWg.Wait()
+	close(confirms)
+	confirmWg.Wait()
+	return newReport(start, results, g.config.Confirm), nil
+}
+
+// dispatch hands out the indexes of transactions at the configured rate
+func (g *Generator) dispatch(ctx context.Context, jobs chan<- int) {
+	defer close(jobs)
+	var tick <-chan time.Time
+	if g.config.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(g.config.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for i := range g.bodies {
+		if tick != nil && i > 0 {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (g *Generator) send(body []byte, url string) *result {
+	stat, err := g.rpc.FastInvokeContract(body, url)
+	res := &result{requestTime: stat.RequestTime, responseTime: stat.ResponseTime}
+	if err != nil {
+		res.sendErr = &ErrorCount{Code: err.Code(), Message: err.Error()}
+		return res
+	}
+	var resp rpc.JSONResponse
+	if err := json.Unmarshal(stat.TxReceipt, &resp); err != nil {
+		res.sendErr = &ErrorCount{Code: rpc.SystemErrorCode, Message: err.Error()}
+	} else if resp.Code != rpc.SuccessCode {
+		res.sendErr = &ErrorCount{Code: resp.Code, Message: resp.Message}
+	} else {
+		_ = json.Unmarshal(resp.Result, &res.hash)
+	}
+	return res
+}
+
+func (g *Generator) confirm(res *result) {
+	receipt, err, _ := g.rpc.WaitReceipt(res.hash, false)
+	switch {
+	case err != nil:
+		res.confirmErr = &ErrorCount{Code: err.Code(), Message: err.Error()}
+	case !receipt.Valid:
+		res.confirmErr = &ErrorCount{Code: rpc.SuccessCode, Message: "invalid transaction: " + receipt.ErrorMsg}
+	default:
+		res.confirmTime = time.Now()
+	}
+}
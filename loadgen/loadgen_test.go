@@ -0,0 +1,210 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperchain/gosdk/account"
+	"github.com/hyperchain/gosdk/common"
+	"github.com/hyperchain/gosdk/rpc"
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTo = "0x6201cb0448964ac597faf6fdf1f472edf2a22b89"
+
+func newRPCWithServer(t *testing.T, nodeNum int) (*rpc.RPC, *rpctest.Server, func()) {
+	srv := rpctest.NewServer(nodeNum)
+	dir, err := srv.ConfDir()
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	rp := rpc.NewRPCWithPath(dir)
+	return rp, srv, func() {
+		rp.Close()
+		srv.Close()
+	}
+}
+
+func testSigners(t *testing.T, n int) []account.Signer {
+	signers := make([]account.Signer, n)
+	for i := range signers {
+		key, err := account.NewAccountFromPriv(fmt.Sprintf("%064x", i+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers[i] = key
+	}
+	return signers
+}
+
+func TestGenerator_Transfer(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 4)
+	defer closeFn()
+
+	signers := testSigners(t, 3)
+	gen, err := New(rp, Config{
+		Signers:      signers,
+		Transactions: 20,
+		To:           testTo,
+		Concurrency:  4,
+		Confirm:      true,
+	})
+	assert.Nil(t, err)
+	report, err := gen.Run(context.Background())
+	assert.Nil(t, err)
+
+	assert.Equal(t, 20, report.Transactions)
+	assert.Equal(t, 20, report.Accepted)
+	assert.Equal(t, 20, report.Confirmed)
+	assert.Empty(t, report.Errors)
+	assert.True(t, report.SendTPS > 0)
+	assert.True(t, report.ConfirmTPS > 0)
+	assert.True(t, report.SendLatency.Min <= report.SendLatency.P50)
+	assert.True(t, report.SendLatency.P99 <= report.SendLatency.Max)
+	assert.NotNil(t, report.ConfirmLatency)
+
+	// spread across nodes and accounts
+	assert.Equal(t, 20, srv.Requests(rpc.TRANSACTION+"sendTransaction"))
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, 5, srv.NodeRequests(i, rpc.TRANSACTION+"sendTransaction"))
+	}
+	from := make(map[string]int)
+	for _, tx := range srv.Transactions() {
+		from[strings.ToLower(tx.From)]++
+	}
+	assert.Len(t, from, 3)
+}
+
+func TestGenerator_Invoke(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+
+	gen, err := New(rp, Config{
+		Signers:      testSigners(t, 2),
+		Transactions: 6,
+		To:           testTo,
+		Payload:      common.Hex2Bytes("a9059cbb00000001"),
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, gen.Presign())
+	report, err := gen.Run(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 6, report.Accepted)
+	assert.Nil(t, report.ConfirmLatency)
+	assert.Equal(t, 6, srv.Requests(rpc.CONTRACT+"invokeContract"))
+}
+
+func TestGenerator_Rate(t *testing.T) {
+	rp, _, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	gen, err := New(rp, Config{
+		Signers:      testSigners(t, 1),
+		Transactions: 5,
+		To:           testTo,
+		Rate:         50,
+	})
+	assert.Nil(t, err)
+	start := time.Now()
+	report, err := gen.Run(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 5, report.Accepted)
+	// 4 intervals of 20ms between 5 transactions
+	assert.True(t, time.Since(start) >= 80*time.Millisecond)
+}
+
+func TestGenerator_Errors(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:  rpc.TRANSACTION + "sendTransaction",
+		Times:   3,
+		Code:    -32004,
+		Message: "system is busy",
+	})
+
+	gen, err := New(rp, Config{
+		Signers:      testSigners(t, 2),
+		Transactions: 10,
+		To:           testTo,
+		Concurrency:  1,
+		Confirm:      true,
+	})
+	assert.Nil(t, err)
+	report, err := gen.Run(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 10, report.Transactions)
+	assert.Equal(t, 7, report.Accepted)
+	assert.Equal(t, 7, report.Confirmed)
+	assert.Equal(t, []ErrorCount{{Stage: "send", Code: -32004, Message: "system is busy", Count: 3}}, report.Errors)
+
+	data, err := report.JSON()
+	assert.Nil(t, err)
+	var decoded Report
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report.Errors, decoded.Errors)
+	assert.Equal(t, report.SendLatency, decoded.SendLatency)
+
+	text := report.String()
+	assert.Contains(t, text, "transactions: 10, accepted: 7, confirmed: 7")
+	assert.Contains(t, text, "system is busy")
+}
+
+func TestGenerator_Cancel(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	// the receipts are not ready when sending is stopped
+	srv.SetReceiptDelay(8)
+
+	gen, err := New(rp, Config{
+		Signers:      testSigners(t, 1),
+		Transactions: 100,
+		To:           testTo,
+		Rate:         10,
+		Confirm:      true,
+	})
+	assert.Nil(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	report, err := gen.Run(ctx)
+	assert.Nil(t, err)
+	assert.True(t, report.Transactions > 0 && report.Transactions < 100)
+	assert.Equal(t, report.Transactions, report.Accepted)
+	// the sent transactions are still confirmed
+	assert.Equal(t, report.Accepted, report.Confirmed)
+	assert.Empty(t, report.Errors)
+}
+
+func TestNew(t *testing.T) {
+	rp, _, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	signers := testSigners(t, 1)
+	for _, config := range []Config{
+		{Transactions: 1, To: testTo},
+		{Signers: signers, To: testTo},
+		{Signers: signers, Transactions: 1},
+		{Signers: signers, Transactions: 1, To: testTo, Rate: -1},
+	} {
+		_, err := New(rp, config)
+		assert.NotNil(t, err)
+	}
+	gen, err := New(rp, Config{Signers: signers, Transactions: 1, To: testTo})
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultConcurrency, gen.config.Concurrency)
+	assert.Equal(t, DefaultConcurrency, gen.config.ConfirmConcurrency)
+	assert.Equal(t, rpc.EVM, gen.config.VMType)
+}
+
+func TestPercentile(t *testing.T) {
+	latency := newLatency([]time.Duration{10, 2, 8, 4, 6, 1, 3, 5, 9, 7})
+	assert.Equal(t, Latency{Min: 1, Mean: 5, P50: 5, P90: 9, P95: 10, P99: 10, Max: 10}, latency)
+	assert.Equal(t, Latency{}, newLatency(nil))
+}
@@ -0,0 +1,192 @@
+package loadgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report is the result of a run, durations are in nanoseconds in json
+type Report struct {
+	// Transactions is the number of transactions sent, the ones skipped are not included
+	Transactions int `json:"transactions"`
+	// Accepted is the number of transactions accepted by nodes
+	Accepted int `json:"accepted"`
+	// Confirmed is the number of transactions whose valid receipts are got
+	Confirmed int `json:"confirmed"`
+	// SendDuration is the duration from the first request to the last response
+	SendDuration time.Duration `json:"sendDuration"`
+	// ConfirmDuration is the duration from the first request to the last confirmation
+	ConfirmDuration time.Duration `json:"confirmDuration,omitempty"`
+	// SendTPS is the number of accepted transactions per second
+	SendTPS float64 `json:"sendTPS"`
+	// ConfirmTPS is the number of confirmed transactions per second
+	ConfirmTPS float64 `json:"confirmTPS,omitempty"`
+	// SendLatency is the latency from the request to the response of accepted transactions
+	SendLatency Latency `json:"sendLatency"`
+	// ConfirmLatency is the latency from the request to the confirmation of confirmed transactions
+	ConfirmLatency *Latency `json:"confirmLatency,omitempty"`
+	// Errors is the number of errors of each code, in descending order of count
+	Errors []ErrorCount `json:"errors,omitempty"`
+}
+
+// Latency is the distribution of latencies
+type Latency struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// ErrorCount is the number of errors with the same code in the same stage
+type ErrorCount struct {
+	// Stage is "send" or "confirm"
+	Stage string `json:"stage"`
+	Code  int    `json:"code"`
+	// Message is the message of the first error
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+func newReport(start time.Time, results []*result, confirm bool) *Report {
+	r := new(Report)
+	var (
+		sendLatencies, confirmLatencies []time.Duration
+		lastResponse, lastConfirm       = start, start
+	)
+	errs := make(map[string]*ErrorCount)
+	addErr := func(stage string, e *ErrorCount) {
+		key := fmt.Sprintf("%s:%d", stage, e.Code)
+		if ec, ok := errs[key]; ok {
+			ec.Count++
+			return
+		}
+		errs[key] = &ErrorCount{Stage: stage, Code: e.Code, Message: e.Message, Count: 1}
+	}
+
+	for _, res := range results {
+		if res == nil {
+			// skipped
+			continue
+		}
+		r.Transactions++
+		if res.responseTime.After(lastResponse) {
+			lastResponse = res.responseTime
+		}
+		if res.sendErr != nil {
+			addErr("send", res.sendErr)
+			continue
+		}
+		r.Accepted++
+		sendLatencies = append(sendLatencies, res.responseTime.Sub(res.requestTime))
+		if !confirm {
+			continue
+		}
+		if res.confirmErr != nil {
+			addErr("confirm", res.confirmErr)
+			continue
+		}
+		r.Confirmed++
+		confirmLatencies = append(confirmLatencies, res.confirmTime.Sub(res.requestTime))
+		if res.confirmTime.After(lastConfirm) {
+			lastConfirm = res.confirmTime
+		}
+	}
+
+	r.SendDuration = lastResponse.Sub(start)
+	r.SendTPS = perSecond(r.Accepted, r.SendDuration)
+	r.SendLatency = newLatency(sendLatencies)
+	if confirm {
+		r.ConfirmDuration = lastConfirm.Sub(start)
+		r.ConfirmTPS = perSecond(r.Confirmed, r.ConfirmDuration)
+		latency := newLatency(confirmLatencies)
+		r.ConfirmLatency = &latency
+	}
+	for _, ec := range errs {
+		r.Errors = append(r.Errors, *ec)
+	}
+	sort.Slice(r.Errors, func(i, j int) bool {
+		if r.Errors[i].Count != r.Errors[j].Count {
+			return r.Errors[i].Count > r.Errors[j].Count
+		}
+		if r.Errors[i].Stage != r.Errors[j].Stage {
+			return r.Errors[i].Stage > r.Errors[j].Stage
+		}
+		return r.Errors[i].Code < r.Errors[j].Code
+	})
+	return r
+}
+
+func perSecond(n int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
+
+func newLatency(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+	return Latency{
+		Min:  latencies[0],
+		Mean: sum / time.Duration(len(latencies)),
+		P50:  percentile(latencies, 0.5),
+		P90:  percentile(latencies, 0.9),
+		P95:  percentile(latencies, 0.95),
+		P99:  percentile(latencies, 0.99),
+		Max:  latencies[len(latencies)-1],
+	}
+}
+
+// percentile return the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// JSON return the report in json
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// String return the report in text
+func (r *Report) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "transactions: %d, accepted: %d", r.Transactions, r.Accepted)
+	if r.ConfirmLatency != nil {
+		fmt.Fprintf(sb, ", confirmed: %d", r.Confirmed)
+	}
+	fmt.Fprintf(sb, "\nsend:    %.2f tps in %v\n", r.SendTPS, r.SendDuration)
+	fmt.Fprintf(sb, "  latency %s\n", r.SendLatency.String())
+	if r.ConfirmLatency != nil {
+		fmt.Fprintf(sb, "confirm: %.2f tps in %v\n", r.ConfirmTPS, r.ConfirmDuration)
+		fmt.Fprintf(sb, "  latency %s\n", r.ConfirmLatency.String())
+	}
+	if len(r.Errors) > 0 {
+		sb.WriteString("errors:\n")
+		for _, e := range r.Errors {
+			fmt.Fprintf(sb, "  %-7s %6d x %d: %s\n", e.Stage, e.Code, e.Count, e.Message)
+		}
+	}
+	return sb.String()
+}
+
+// String return the latencies in text
+func (l Latency) String() string {
+	return fmt.Sprintf("min %v, mean %v, p50 %v, p90 %v, p95 %v, p99 %v, max %v", l.Min, l.Mean, l.P50, l.P90, l.P95, l.P99, l.Max)
+}
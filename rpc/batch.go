@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// BatchRequest collects json rpc calls and posts them as a json rpc array in one http request
+type BatchRequest struct {
+	rpc      *RPC
+	requests []*JSONRequest
+}
+
+// BatchResult is the result of one call in the batch, Error is not nil if the call failed
+type BatchResult struct {
+	ID     int
+	Method string
+	Result json.RawMessage
+	Error  StdError
+	req    *JSONRequest
+}
+
+// NewBatch create an empty batch request, requests are signed by inspector
+// and sent with tcert headers in the same way as single call
+func (rpc *RPC) NewBatch() *BatchRequest {
+	return &BatchRequest{
+		rpc: rpc,
+	}
+}
+
+// Add append a call to the batch, the id of the call is its position starting from 1
+func (br *BatchRequest) Add(method string, params ...interface{}) *BatchRequest {
+	req := br.rpc.jsonRPC(method, params...)
+	req.ID = len(br.requests) + 1
+	br.requests = append(br.requests, req)
+	return br
+}
+
+// AddGetBlockByNumber append block_getBlockByNumber to the batch
+func (br *BatchRequest) AddGetBlockByNumber(blockNum interface{}, isPlain bool) *BatchRequest {
+	return br.Add(BLOCK+"getBlockByNumber", blockNum, isPlain)
+}
+
+// AddGetBlockByHash append block_getBlockByHash to the batch
+func (br *BatchRequest) AddGetBlockByHash(blockHash string, isPlain bool) *BatchRequest {
+	return br.Add(BLOCK+"getBlockByHash", blockHash, isPlain)
+}
+
+// AddGetTransactionByHash append tx_getTransactionByHash to the batch
+func (br *BatchRequest) AddGetTransactionByHash(txHash string) *BatchRequest {
+	return br.Add(TRANSACTION+"getTransactionByHash", txHash)
+}
+
+// AddGetTxReceipt append tx_getTransactionReceipt to the batch
+func (br *BatchRequest) AddGetTxReceipt(txHash string, isPrivateTx bool) *BatchRequest {
+	if isPrivateTx {
+		return br.Add(TRANSACTION+"getPrivateTransactionReceipt", chPrefix(txHash))
+	}
+	return br.Add(TRANSACTION+"getTransactionReceipt", chPrefix(txHash))
+}
+
+// Len return the number of calls in the batch
+func (br *BatchRequest) Len() int {
+	return len(br.requests)
+}
+
+// Send post all calls in one http request, results are in the same order as calls were added.
+// The returned StdError is not nil only if the whole batch failed,
+// the error of each call is set in BatchResult.Error.
+func (br *BatchRequest) Send() ([]*BatchResult, StdError) {
+	if len(br.requests) == 0 {
+		return nil, NewSystemError(errors.New("batch request is empty"))
+	}
+
+	span, rpc := br.rpc.startSpan(batchMethod)
+	span.SetTag(TagMethod, batchMethod)
+	call := &Call{Batch: br.requests}
+	err := rpc.hrm.do(rpc.getContext(), call, GENERAL, nil)
+	finishSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	data := call.Response
+
+	var resps []*JSONResponse
+	if sysErr := json.Unmarshal(data, &resps); sysErr != nil {
+		// node may reply a single error object if it can not handle the batch
+		var resp *JSONResponse
+		if json.Unmarshal(data, &resp) == nil && resp != nil && resp.Code != SuccessCode {
+			return nil, withRequest(NewServerError(resp.Code, resp.Message), call)
+		}
+		return nil, NewSystemError(sysErr)
+	}
+
+	byID := make(map[int]*JSONResponse, len(resps))
+	for _, resp := range resps {
+		if resp != nil {
+			byID[resp.ID] = resp
+		}
+	}
+
+	results := make([]*BatchResult, len(br.requests))
+	for i, req := range br.requests {
+		result := &BatchResult{
+			ID:     req.ID,
+			Method: req.Method,
+			req:    req,
+		}
+		resp, ok := byID[req.ID]
+		single := &Call{Request: req, URL: call.URL}
+		switch {
+		case !ok:
+			result.Error = withRequest(NewGetResponseError(fmt.Errorf("no response for request %d(%s)", req.ID, req.Method)), single)
+		case resp.Code != SuccessCode:
+			result.Error = withRequest(NewServerError(resp.Code, resp.Message), single)
+		default:
+			result.Result = resp.Result
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// Unmarshal decode the result into v
+func (r *BatchResult) Unmarshal(v interface{}) StdError {
+	if r.Error != nil {
+		return r.Error
+	}
+	if sysErr := json.Unmarshal(r.Result, v); sysErr != nil {
+		return NewSystemError(sysErr)
+	}
+	return nil
+}
+
+// Block decode the result of block_getBlockByNumber or block_getBlockByHash
+func (r *BatchResult) Block() (*Block, StdError) {
+	var blockRaw BlockRaw
+	if err := r.Unmarshal(&blockRaw); err != nil {
+		return nil, err
+	}
+	return blockRaw.ToBlock()
+}
+
+// TransactionInfo decode the result of tx_getTransactionByHash
+func (r *BatchResult) TransactionInfo() (*TransactionInfo, StdError) {
+	var raw TransactionRaw
+	if err := r.Unmarshal(&raw); err != nil {
+		return nil, err
+	}
+	return raw.ToTransaction()
+}
+
+// TxReceipt decode the result of tx_getTransactionReceipt
+func (r *BatchResult) TxReceipt() (*TxReceipt, StdError) {
+	var receipt TxReceipt
+	if err := r.Unmarshal(&receipt); err != nil {
+		return nil, err
+	}
+	if r.req != nil && len(r.req.Params) > 0 {
+		if txHash, ok := r.req.Params[0].(string); ok {
+			receipt.PrivTxHash = txHash
+		}
+	}
+	return &receipt, nil
+}
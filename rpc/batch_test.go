@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchRequest_Send(t *testing.T) {
+	var calls int
+	rp, closeFn := newTestRPC(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		var reqs []JSONRequest
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"code":-32600,"message":"invalid request"}`))
+			return
+		}
+		var resps []string
+		// reply in reverse order, and drop the request with id 4
+		for i := len(reqs) - 1; i >= 0; i-- {
+			req := reqs[i]
+			switch req.Method {
+			case "block_getBlockByNumber":
+				resps = append(resps, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"code":0,"result":{"number":"0x%x","hash":"0x01","avgTime":"0x0","txcounts":"0x0"}}`, req.ID, int(req.Params[0].(float64))))
+			case "tx_getTransactionReceipt":
+				resps = append(resps, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"code":-32001,"message":"receipt not exist"}`, req.ID))
+			case "node_getNodeHash":
+			}
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(resps, ",") + "]"))
+	})
+	defer closeFn()
+
+	batch := rp.NewBatch().
+		AddGetBlockByNumber(1, true).
+		AddGetBlockByNumber(2, true).
+		AddGetTxReceipt("1234", false).
+		Add("node_getNodeHash")
+	assert.Equal(t, 4, batch.Len())
+
+	results, err := batch.Send()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Len(t, results, 4)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, i+1, results[i].ID)
+		block, err := results[i].Block()
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(i+1), block.Number)
+	}
+
+	_, err = results[2].TxReceipt()
+	assert.Equal(t, DataNotExistCode, err.Code())
+	assert.Equal(t, "tx_getTransactionReceipt", results[2].Method)
+
+	assert.Equal(t, GetResponseErrorCode, results[3].Error.Code())
+
+	_, err = rp.NewBatch().Send()
+	assert.NotNil(t, err)
+}
+
+func TestBatchRequest_NotSupported(t *testing.T) {
+	rp, closeFn := newTestRPC(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":0,"code":-32600,"message":"invalid request"}`))
+	})
+	defer closeFn()
+
+	results, err := rp.NewBatch().Add("node_getNodes").Send()
+	assert.Nil(t, results)
+	assert.Equal(t, -32600, err.Code())
+}
@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperchain/gosdk/account"
+	"github.com/hyperchain/gosdk/common"
+)
+
+// selectors of the revert data of solidity
+var (
+	revertErrorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	revertPanicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// ContractABI encodes the input and decodes the output of contract methods for CallContract,
+// it is implemented by abi.ABI for EVM contracts and hvm.Abi for HVM contracts
+type ContractABI interface {
+	// VMType return the vm type of the contract, "EVM" or "HVM"
+	VMType() string
+	// EncodeInput return the payload invoking method with args
+	EncodeInput(method string, args ...interface{}) ([]byte, error)
+	// DecodeOutput decode the return value of method
+	DecodeOutput(method string, ret []byte) ([]interface{}, error)
+}
+
+// ContractError is the error of a failed contract invocation, it matches ErrContractInvoke
+type ContractError struct {
+	*RetError
+	// Reason is the decoded revert reason, or the message of node if the revert data can not be decoded
+	Reason string
+	// Data is the raw revert data, nil if node did not return any
+	Data []byte
+}
+
+func (e *ContractError) Error() string {
+	return "contract invocation failed: " + e.Reason
+}
+
+func (e *ContractError) String() string {
+	return fmt.Sprintf("error code: %d, error reason: %s", e.Code(), e.Error())
+}
+
+// CallContract simulates invoking method of the contract at address with args, which reads the contract state
+// without a transaction on chain, and return the decoded return values. The transaction is signed by key, an
+// account.Key or account.Signer, or by an ephemeral key if key is nil. A failed invocation returns a
+// *ContractError with the decoded revert reason.
+func (rpc *RPC) CallContract(address string, contractABI ContractABI, method string, key account.Signer, args ...interface{}) ([]interface{}, StdError) {
+	payload, err := contractABI.EncodeInput(method, args...)
+	if err != nil {
+		return nil, NewSystemError(err)
+	}
+	if key == nil {
+		ephemeral, err := ephemeralKey()
+		if err != nil {
+			return nil, NewSystemError(err)
+		}
+		key = ephemeral
+	}
+
+	transaction := NewTransaction(key.GetAddress().Hex()).Invoke(address, payload).
+		VMType(VMType(contractABI.VMType())).Simulate(true)
+	transaction.txVersion = rpc.txVersion
+	transaction.Sign(key)
+	receipt, stdErr := rpc.InvokeContract(transaction)
+	if stdErr != nil {
+		var re *RetError
+		if errors.As(stdErr, &re) && re.Code() == ContractInvokeErrorCode {
+			return nil, newContractError(re, re.Error())
+		}
+		return nil, stdErr
+	}
+	if !receipt.Valid {
+		re := &RetError{code: ContractInvokeErrorCode, message: receipt.ErrorMsg}
+		if receipt.ErrorMsg == "" {
+			return nil, newContractError(re, receipt.Ret)
+		}
+		return nil, newContractError(re, receipt.ErrorMsg)
+	}
+
+	values, err := contractABI.DecodeOutput(method, common.FromHex(receipt.Ret))
+	if err != nil {
+		return nil, NewSystemError(err)
+	}
+	return values, nil
+}
+
+// newContractError return the ContractError of re, the revert reason is decoded from data if it is hex
+func newContractError(re *RetError, data string) *ContractError {
+	ce := &ContractError{RetError: re, Reason: data}
+	raw, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if err != nil || len(raw) == 0 {
+		return ce
+	}
+	ce.Data = raw
+	if reason, ok := decodeRevert(raw); ok {
+		ce.Reason = reason
+	}
+	return ce
+}
+
+// decodeRevert decode the reason of Error(string) or the code of Panic(uint256) reverted by solidity
+func decodeRevert(data []byte) (string, bool) {
+	if len(data) < 4 {
+		return "", false
+	}
+	selector, body := data[:4], data[4:]
+	switch {
+	case string(selector) == string(revertErrorSelector):
+		// offset of the string, then the length and the content
+		if len(body) < 64 {
+			return "", false
+		}
+		offset, ok := readWord(body[:32])
+		if !ok || offset > uint64(len(body))-32 {
+			return "", false
+		}
+		length, ok := readWord(body[offset : offset+32])
+		if !ok || length > uint64(len(body))-offset-32 {
+			return "", false
+		}
+		return string(body[offset+32 : offset+32+length]), true
+	case string(selector) == string(revertPanicSelector):
+		if len(body) < 32 {
+			return "", false
+		}
+		code, ok := readWord(body[:32])
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("panic code 0x%x", code), true
+	default:
+		return "", false
+	}
+}
+
+// readWord read a 32 bytes big endian word which should fit in uint64
+func readWord(word []byte) (uint64, bool) {
+	for _, b := range word[:24] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return binary.BigEndian.Uint64(word[24:]), true
+}
+
+// ephemeralKey return a new ecdsa key signing simulated transactions
+func ephemeralKey() (*account.ECDSAKey, error) {
+	var priv [32]byte
+	if _, err := rand.Read(priv[:]); err != nil {
+		return nil, err
+	}
+	// keep the key below the order of secp256k1
+	priv[0] &= 0x7f
+	priv[31] |= 0x01
+	return account.NewAccountFromPriv(hex.EncodeToString(priv[:]))
+}
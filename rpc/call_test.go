@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hyperchain/gosdk/abi"
+	"github.com/hyperchain/gosdk/common"
+	"github.com/hyperchain/gosdk/hvm"
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+const callTestABI = `[{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"get","outputs":[{"name":"","type":"uint256"},{"name":"","type":"string"}],"payable":false,"type":"function"}]`
+
+func deployTestContract(t *testing.T, rp *RPC, vmType VMType) string {
+	receipt, err := rp.SignAndDeployContract(NewTransaction(testFrom).Deploy("0x6060").VMType(vmType), testKeys(t)["ecdsa"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receipt.ContractAddress
+}
+
+// revertData return the revert data of solidity Error(reason)
+func revertData(reason string) string {
+	word := func(n int) []byte {
+		return common.LeftPadBytes(big.NewInt(int64(n)).Bytes(), 32)
+	}
+	data := append([]byte{}, revertErrorSelector...)
+	data = append(data, word(32)...)
+	data = append(data, word(len(reason))...)
+	data = append(data, common.RightPadBytes([]byte(reason), (len(reason)+31)/32*32)...)
+	return "0x" + hex.EncodeToString(data)
+}
+
+func TestRPC_CallContract(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+	address := deployTestContract(t, rp, EVM)
+
+	ABI, err := abi.JSON(strings.NewReader(callTestABI))
+	assert.Nil(t, err)
+	ret, err := ABI.Methods["get"].Outputs.Pack(big.NewInt(7), "seven")
+	assert.Nil(t, err)
+	var invoked []*rpctest.Tx
+	srv.HandleContract(func(tx *rpctest.Tx) (string, error) {
+		invoked = append(invoked, tx)
+		return common.ToHex(ret), nil
+	})
+
+	key := testKeys(t)["sm2"]
+	values, stdErr := rp.CallContract(address, ABI, "get", key, big.NewInt(1))
+	assert.Nil(t, stdErr)
+	assert.Equal(t, []interface{}{big.NewInt(7), "seven"}, values)
+
+	// an ephemeral key signs if no key is given
+	values, stdErr = rp.CallContract(address, &ABI, "get", nil, big.NewInt(1))
+	assert.Nil(t, stdErr)
+	assert.Len(t, values, 2)
+
+	assert.Len(t, invoked, 2)
+	input, _ := ABI.Pack("get", big.NewInt(1))
+	for _, tx := range invoked {
+		assert.True(t, tx.Simulate)
+		assert.Equal(t, string(EVM), tx.VMType)
+		assert.Equal(t, common.ToHex(input), chPrefix(tx.Payload))
+	}
+	assert.Equal(t, strings.ToLower(key.GetAddress().Hex()), invoked[0].From)
+	// simulated invocations are not committed
+	assert.Equal(t, uint64(1), srv.Height())
+
+	_, stdErr = rp.CallContract(address, ABI, "notExist", nil)
+	assert.True(t, errors.Is(stdErr, ErrSystem))
+}
+
+func TestRPC_CallContract_Revert(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+	address := deployTestContract(t, rp, EVM)
+	ABI, _ := abi.JSON(strings.NewReader(callTestABI))
+
+	panicData := append(append([]byte{}, revertPanicSelector...), common.LeftPadBytes([]byte{0x11}, 32)...)
+	for message, reason := range map[string]string{
+		revertData("insufficient balance"): "insufficient balance",
+		common.ToHex(panicData):            "panic code 0x11",
+		"out of gas":                       "out of gas",
+	} {
+		message := message
+		srv.HandleContract(func(tx *rpctest.Tx) (string, error) {
+			return "", errors.New(message)
+		})
+		_, stdErr := rp.CallContract(address, ABI, "get", nil, big.NewInt(1))
+		assert.True(t, errors.Is(stdErr, ErrContractInvoke))
+		assert.Equal(t, ContractInvokeErrorCode, stdErr.Code())
+		var ce *ContractError
+		assert.True(t, errors.As(stdErr, &ce))
+		assert.Equal(t, reason, ce.Reason)
+		assert.Contains(t, ce.Error(), reason)
+	}
+
+	// the contract does not exist
+	_, stdErr := rp.CallContract("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", ABI, "get", nil, big.NewInt(1))
+	assert.True(t, errors.Is(stdErr, ErrContractInvoke))
+}
+
+func TestRPC_CallContract_HVM(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+	address := deployTestContract(t, rp, HVM)
+
+	abiJson, err := common.ReadFileAsString("../hvmtestfile/fibonacci/hvm.abi")
+	assert.Nil(t, err)
+	hvmABI, err := hvm.GenAbi(abiJson)
+	assert.Nil(t, err)
+	srv.HandleContract(func(tx *rpctest.Tx) (string, error) {
+		if tx.VMType != string(HVM) {
+			return "", errors.New("not hvm")
+		}
+		return common.ToHex([]byte("55")), nil
+	})
+
+	values, stdErr := rp.CallContract(address, hvmABI, "invoke.InvokeFibonacci", nil)
+	assert.Nil(t, stdErr)
+	assert.Len(t, values, 1)
+	assert.Equal(t, json.Number("55"), values[0])
+}
@@ -0,0 +1,138 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperchain/gosdk/account"
+	"github.com/hyperchain/gosdk/common"
+)
+
+// WithContext generate a new RPC instance whose calls are bound to ctx.
+// When ctx is canceled or its deadline is exceeded, in-flight http requests
+// are aborted and receipt polling stops with RequestCanceledErrorCode.
+func (rpc *RPC) WithContext(ctx context.Context) *RPC {
+	if ctx == nil {
+		panic("nil context")
+	}
+	proxy := *rpc
+	proxy.ctx = ctx
+	return &proxy
+}
+
+// getContext return the context bound to rpc, context.Background() if none
+func (rpc *RPC) getContext() context.Context {
+	if rpc.ctx == nil {
+		return context.Background()
+	}
+	return rpc.ctx
+}
+
+// pollWait sleep interval milliseconds between two polling, return early if the context is done
+func (rpc *RPC) pollWait(interval int64) StdError {
+	ctx := rpc.getContext()
+	timer := time.NewTimer(time.Millisecond * time.Duration(interval))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return NewRequestCanceledError(ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// CallCtx is the context-aware variant of Call
+func (rpc *RPC) CallCtx(ctx context.Context, method string, param interface{}) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).Call(method, param)
+}
+
+// CallByPollingCtx is the context-aware variant of CallByPolling
+func (rpc *RPC) CallByPollingCtx(ctx context.Context, method string, param interface{}, isPrivateTx bool) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).CallByPolling(method, param, isPrivateTx)
+}
+
+// CallTxByPollingCtx is the context-aware variant of CallTxByPolling
+func (rpc *RPC) CallTxByPollingCtx(ctx context.Context, method string, transaction *Transaction) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).CallTxByPolling(method, transaction)
+}
+
+// GetTxReceiptCtx is the context-aware variant of GetTxReceipt
+func (rpc *RPC) GetTxReceiptCtx(ctx context.Context, txHash string, isPrivateTx bool) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).GetTxReceipt(txHash, isPrivateTx)
+}
+
+// GetTxReceiptByPollingCtx is the context-aware variant of GetTxReceiptByPolling,
+// polling stops as soon as ctx is done
+func (rpc *RPC) GetTxReceiptByPollingCtx(ctx context.Context, txHash string, isPrivateTx bool) (*TxReceipt, StdError, bool) {
+	return rpc.WithContext(ctx).GetTxReceiptByPolling(txHash, isPrivateTx)
+}
+
+// SendTxCtx is the context-aware variant of SendTx
+func (rpc *RPC) SendTxCtx(ctx context.Context, transaction *Transaction) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).SendTx(transaction)
+}
+
+// SignAndSendTxCtx is the context-aware variant of SignAndSendTx
+func (rpc *RPC) SignAndSendTxCtx(ctx context.Context, transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).SignAndSendTx(transaction, key)
+}
+
+// DeployContractCtx is the context-aware variant of DeployContract
+func (rpc *RPC) DeployContractCtx(ctx context.Context, transaction *Transaction) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).DeployContract(transaction)
+}
+
+// SignAndDeployContractCtx is the context-aware variant of SignAndDeployContract
+func (rpc *RPC) SignAndDeployContractCtx(ctx context.Context, transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).SignAndDeployContract(transaction, key)
+}
+
+// InvokeContractCtx is the context-aware variant of InvokeContract
+func (rpc *RPC) InvokeContractCtx(ctx context.Context, transaction *Transaction) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).InvokeContract(transaction)
+}
+
+// SignAndInvokeContractCtx is the context-aware variant of SignAndInvokeContract
+func (rpc *RPC) SignAndInvokeContractCtx(ctx context.Context, transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).SignAndInvokeContract(transaction, key)
+}
+
+// MaintainContractCtx is the context-aware variant of MaintainContract
+func (rpc *RPC) MaintainContractCtx(ctx context.Context, transaction *Transaction) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).MaintainContract(transaction)
+}
+
+// SignAndMaintainContractCtx is the context-aware variant of SignAndMaintainContract
+func (rpc *RPC) SignAndMaintainContractCtx(ctx context.Context, transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).SignAndMaintainContract(transaction, key)
+}
+
+// SignAndManageContractByVoteCtx is the context-aware variant of SignAndManageContractByVote
+func (rpc *RPC) SignAndManageContractByVoteCtx(ctx context.Context, transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
+	return rpc.WithContext(ctx).SignAndManageContractByVote(transaction, key)
+}
+
+// GetBlocksCtx is the context-aware variant of GetBlocks
+func (rpc *RPC) GetBlocksCtx(ctx context.Context, from, to uint64, isPlain bool) ([]*Block, StdError) {
+	return rpc.WithContext(ctx).GetBlocks(from, to, isPlain)
+}
+
+// GetBlockByNumberCtx is the context-aware variant of GetBlockByNumber
+func (rpc *RPC) GetBlockByNumberCtx(ctx context.Context, blockNum interface{}, isPlain bool) (*Block, StdError) {
+	return rpc.WithContext(ctx).GetBlockByNumber(blockNum, isPlain)
+}
+
+// GetTransactionByHashCtx is the context-aware variant of GetTransactionByHash
+func (rpc *RPC) GetTransactionByHashCtx(ctx context.Context, txHash string) (*TransactionInfo, StdError) {
+	return rpc.WithContext(ctx).GetTransactionByHash(txHash)
+}
+
+// FileUploadCtx is the context-aware variant of FileUpload
+func (rpc *RPC) FileUploadCtx(ctx context.Context, filePath string, description string, whiteList []common.Address, nodeID int, accountJson string, password string) (string, StdError) {
+	return rpc.WithContext(ctx).FileUpload(filePath, description, whiteList, nodeID, accountJson, password)
+}
+
+// FileDownloadCtx is the context-aware variant of FileDownload
+func (rpc *RPC) FileDownloadCtx(ctx context.Context, fileDownloadTX *Transaction, tarPath string, hash string, nodeID int) (string, StdError) {
+	return rpc.WithContext(ctx).FileDownload(fileDownloadTX, tarPath, hash, nodeID)
+}
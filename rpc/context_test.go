@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRPC(t *testing.T, handler http.HandlerFunc) (*RPC, func()) {
+	server := httptest.NewServer(handler)
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return DefaultRPC(NewNode(host, port, "")), server.Close
+}
+
+func TestRPC_WithContextTimeout(t *testing.T) {
+	rp, closeFn := newTestRPC(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	})
+	defer closeFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := rp.WithContext(ctx).GetNodes()
+	assert.NotNil(t, err)
+	assert.Equal(t, RequestCanceledErrorCode, err.Code())
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestRPC_GetTxReceiptByPollingCtx(t *testing.T) {
+	rp, closeFn := newTestRPC(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"code":-32001,"message":"not exist"}`))
+	})
+	defer closeFn()
+	rp.FirstPollInterval(1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	receipt, err, stop := rp.GetTxReceiptByPollingCtx(ctx, "0x1234", false)
+	assert.Nil(t, receipt)
+	assert.True(t, stop)
+	assert.Equal(t, RequestCanceledErrorCode, err.Code())
+	assert.True(t, time.Since(start) < time.Second)
+
+	_, err = rp.WithContext(ctx).GetTxReceipt("0x1234", false)
+	assert.Equal(t, RequestCanceledErrorCode, err.Code())
+}
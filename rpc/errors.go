@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"errors"
+)
+
+// Sentinel errors, a StdError returned by rpc matches the sentinel of its code by errors.Is:
+//
+//	if errors.Is(err, rpc.ErrDataNotExist) {
+//		...
+//	}
+var (
+	// ErrDataNotExist means the data, such as a receipt, does not exist on chain (yet)
+	ErrDataNotExist = newSentinel(DataNotExistCode, "data not exist")
+	// ErrBalanceInsufficient means the balance of the account is insufficient
+	ErrBalanceInsufficient = newSentinel(BalanceInsufficientCode, "balance insufficient")
+	// ErrContractInvoke means the contract invocation failed, e.g. it is reverted
+	ErrContractInvoke = newSentinel(ContractInvokeErrorCode, "contract invocation failed")
+	// ErrSystemBusy means the node is too busy to handle the request
+	ErrSystemBusy = newSentinel(SystemBusyCode, "system is busy")
+	// ErrDuplicateTx means the transaction has been received by node
+	ErrDuplicateTx = newSentinel(DuplicateTransactionsCode, "duplicate transaction")
+	// ErrMethodNotExist means the method is not supported by node
+	ErrMethodNotExist = newSentinel(MethodNotExistOrInvalidCode, "method does not exist or is invalid")
+	// ErrNodeUnavailable means no node is available or the node did not respond correctly
+	ErrNodeUnavailable = newSentinel(GetResponseErrorCode, "node is unavailable")
+	// ErrTimeout means the request or receipt polling timed out, including the deadline of context is exceeded
+	ErrTimeout = newSentinel(RequestTimeoutErrorCode, "request time out")
+	// ErrCanceled means the context of the request is canceled
+	ErrCanceled = newSentinel(RequestCanceledErrorCode, "request is canceled")
+	// ErrSystem means an error of sdk, such as a failure of serialization or signing
+	ErrSystem = newSentinel(SystemErrorCode, "system error")
+)
+
+// Sentinel errors of Submitter, their code is AsnycRequestErrorCode
+var (
+	// ErrQueueFull means the queue of the submitter is full
+	ErrQueueFull = &RetError{code: AsnycRequestErrorCode, message: "submit queue is full"}
+	// ErrSubmitterClosed means the submitter is closed
+	ErrSubmitterClosed = &RetError{code: AsnycRequestErrorCode, message: "submitter is closed"}
+)
+
+// sentinels is the sentinel error of each code
+var sentinels = make(map[int]*RetError)
+
+func newSentinel(code int, message string) *RetError {
+	re := &RetError{code: code, message: message}
+	sentinels[code] = re
+	return re
+}
+
+// IsRetryable return whether err is temporary, so the request may succeed if it is sent again later.
+// Errors matching ErrDataNotExist, ErrSystemBusy, ErrNodeUnavailable and ErrQueueFull are retryable.
+func IsRetryable(err error) bool {
+	var re *RetError
+	if errors.As(err, &re) {
+		return re.IsRetryable()
+	}
+	return false
+}
+
+// newPollingTimeoutError return the error of receipt polling which did not get the receipt,
+// its code is GetResponseErrorCode for compatibility, but it matches ErrTimeout
+func newPollingTimeoutError() StdError {
+	return &RetError{
+		code:    GetResponseErrorCode,
+		message: "polling failure",
+		kind:    ErrTimeout,
+	}
+}
+
+// newSubmitError return the error of Submitter matching the sentinel kind
+func newSubmitError(kind *RetError) StdError {
+	return &RetError{
+		code:    kind.code,
+		message: kind.message,
+		kind:    kind,
+	}
+}
+
+// withRequest return a copy of err carrying the method and url of the failed call,
+// err is returned as it is if it is not a RetError or they are set already
+func withRequest(err StdError, call *Call) StdError {
+	re, ok := err.(*RetError)
+	if !ok || re.method != "" || re.url != "" {
+		return err
+	}
+	cp := *re
+	cp.url = call.URL
+	if call.Request != nil {
+		cp.method = call.Request.Method
+	} else if call.Batch != nil {
+		cp.method = batchMethod
+	}
+	return &cp
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetError_Is(t *testing.T) {
+	err := NewServerError(DataNotExistCode, "receipt does not exist")
+	assert.True(t, errors.Is(err, ErrDataNotExist))
+	assert.False(t, errors.Is(err, ErrSystemBusy))
+	assert.True(t, IsRetryable(err))
+	assert.True(t, IsRetryable(fmt.Errorf("wrapped: %w", err)))
+
+	cause := errors.New("connection refused")
+	err = NewGetResponseError(cause)
+	assert.True(t, errors.Is(err, ErrNodeUnavailable))
+	assert.True(t, errors.Is(err, cause))
+	assert.True(t, IsRetryable(err))
+
+	err = NewServerError(BalanceInsufficientCode, "balance insufficient")
+	assert.True(t, errors.Is(err, ErrBalanceInsufficient))
+	assert.False(t, IsRetryable(err))
+	assert.False(t, IsRetryable(NewServerError(DuplicateTransactionsCode, "duplicate")))
+	assert.False(t, IsRetryable(errors.New("plain error")))
+	assert.False(t, IsRetryable(nil))
+
+	err = NewRequestCanceledError(context.Canceled)
+	assert.True(t, errors.Is(err, ErrCanceled))
+	assert.False(t, errors.Is(err, ErrTimeout))
+	err = NewRequestCanceledError(context.DeadlineExceeded)
+	assert.Equal(t, RequestCanceledErrorCode, err.Code())
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	err = newPollingTimeoutError()
+	assert.Equal(t, GetResponseErrorCode, err.Code())
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.False(t, errors.Is(err, ErrNodeUnavailable))
+	assert.False(t, IsRetryable(err))
+}
+
+func TestRetError_Request(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	_, err := rp.GetTransactionByHash("0x1234")
+	assert.True(t, errors.Is(err, ErrDataNotExist))
+	var re *RetError
+	assert.True(t, errors.As(err, &re))
+	assert.Equal(t, TRANSACTION+"getTransactionByHash", re.Method())
+	assert.Equal(t, rp.hrm.nodes[0].url, re.URL())
+
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{Drop: true})
+	_, err = rp.GetNodes()
+	assert.True(t, errors.Is(err, ErrNodeUnavailable))
+	assert.NotNil(t, errors.Unwrap(err))
+	assert.Equal(t, NODE+"getNodes", err.(*RetError).Method())
+	srv.ClearFaults()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{Delay: 200 * time.Millisecond})
+	rp.hrm.nodes[0].setAvailable(true)
+	_, err = rp.WithContext(ctx).GetNodes()
+	assert.True(t, errors.Is(err, ErrTimeout))
+}
+
+func TestCallByPolling_ResendRetryable(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:  TRANSACTION + "sendTransaction",
+		Times:   1,
+		Code:    SystemBusyCode,
+		Message: "system is busy",
+	})
+	receipt, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.Nil(t, err)
+	assert.True(t, receipt.Valid)
+	assert.Equal(t, 2, srv.Requests(TRANSACTION+"sendTransaction"))
+
+	// a non-retryable error is returned at once
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:  TRANSACTION + "sendTransaction",
+		Times:   1,
+		Code:    BalanceInsufficientCode,
+		Message: "balance insufficient",
+	})
+	_, err = rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 2))
+	assert.True(t, errors.Is(err, ErrBalanceInsufficient))
+	assert.Equal(t, 3, srv.Requests(TRANSACTION+"sendTransaction"))
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"time"
 )
 
@@ -13,7 +14,7 @@ type RpcStatistic struct {
 func (rpc *RPC) FastInvokeContract(body []byte, randomURL string) (*RpcStatistic, StdError) {
 	requestTime := time.Now()
 	logger.Debug("invoke contract server url,", randomURL)
-	ret, err := rpc.hrm.SyncRequestSpecificURL(body, randomURL, GENERAL, nil, nil)
+	ret, err := rpc.hrm.SyncRequestSpecificURLWithContext(rpc.getContext(), body, randomURL, GENERAL, nil, nil)
 	responseTime := time.Now()
 	return &RpcStatistic{
 		TxReceipt:    ret,
@@ -21,3 +22,56 @@ func (rpc *RPC) FastInvokeContract(body []byte, randomURL string) (*RpcStatistic
 		ResponseTime: responseTime,
 	}, err
 }
+
+// TxRequestBody return the json rpc request body sending the signed transaction, which is sent by FastInvokeContract.
+// The method is chosen as SendTx, DeployContract, InvokeContract and MaintainContract do according to how it is built.
+// The inspector authentication is signed into the body if inspector is enabled, so it is not signed again when sent.
+func (rpc *RPC) TxRequestBody(transaction *Transaction) ([]byte, StdError) {
+	call := &Call{Request: rpc.jsonRPC(txMethod(transaction), transaction.Serialize())}
+	stdErr := authInterceptor(rpc.im)(rpc.getContext(), call, func(ctx context.Context, call *Call) StdError {
+		return nil
+	})
+	if stdErr != nil {
+		return nil, stdErr
+	}
+	return call.Body()
+}
+
+// NodeURLs return the urls of all nodes, which are used by FastInvokeContract
+func (rpc *RPC) NodeURLs() []string {
+	urls := make([]string, len(rpc.hrm.nodes))
+	for i, node := range rpc.hrm.nodes {
+		urls[i] = node.url
+	}
+	return urls
+}
+
+// txMethod return the method sending the transaction according to how it is built
+func txMethod(transaction *Transaction) string {
+	simulate := !isTxVersion10(transaction.getTxVersion()) && transaction.simulate
+	switch {
+	case transaction.isDeploy:
+		if transaction.isPrivateTx {
+			return CONTRACT + "deployPrivateContract"
+		}
+		if simulate {
+			return SIMULATE + "deployContract"
+		}
+		return CONTRACT + "deployContract"
+	case transaction.isMaintain:
+		if simulate {
+			return SIMULATE + "maintainContract"
+		}
+		return CONTRACT + "maintainContract"
+	case transaction.isInvoke:
+		if transaction.isPrivateTx {
+			return CONTRACT + "invokePrivateContract"
+		}
+		if simulate {
+			return SIMULATE + "invokeContract"
+		}
+		return CONTRACT + "invokeContract"
+	default:
+		return TRANSACTION + "sendTransaction"
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/pkg/errors"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -29,28 +30,23 @@ func (rpc *RPC) callFM(method string, nodeID int, requestType RequestType, extra
 		return nil, gerr
 	}
 
-	jsonRequest := rpc.jsonRPC(method, params...)
-	bytesRequest, sysErr := json.Marshal(jsonRequest)
-	if sysErr != nil {
-		return nil, NewSystemError(sysErr)
+	span, rpc := rpc.startSpan(method)
+	span.SetTag(TagMethod, method)
+	call := &Call{
+		Request: rpc.jsonRPC(method, params...),
+		URL:     url,
+		Header:  make(http.Header),
 	}
-	extraHeaders["params"] = string(bytesRequest)
-
-	data, err := rpc.hrm.SyncRequestSpecificURL(bytesRequest, url, requestType, extraHeaders, rwSeeker)
-	if err != nil {
-		return nil, err
+	for k, v := range extraHeaders {
+		call.Header.Add(k, v)
 	}
-
-	var resp *JSONResponse
-	if sysErr = json.Unmarshal(data, &resp); sysErr != nil {
-		return nil, NewSystemError(sysErr)
+	err := rpc.hrm.do(rpc.getContext(), call, requestType, rwSeeker)
+	var data json.RawMessage
+	if err == nil {
+		data, err = call.result()
 	}
-
-	if resp.Code != SuccessCode {
-		return nil, NewServerError(resp.Code, resp.Message)
-	}
-
-	return resp.Result, nil
+	finishSpan(span, err)
+	return data, err
 }
 
 // FileUpload 文件上传接口
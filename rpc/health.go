@@ -0,0 +1,339 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperchain/gosdk/common"
+)
+
+const (
+	// DefaultFailureThreshold is the number of consecutive failures that opens the breaker of a node
+	DefaultFailureThreshold = 1
+	// DefaultProbeInterval is the first interval(ms) between two probes of a bad node,
+	// the interval doubles after each failed probe until reConnectTime
+	DefaultProbeInterval = 500
+	// timeout of one probe request
+	probeTimeout = 10 * time.Second
+)
+
+// BreakerState is the state of the circuit breaker of a node
+type BreakerState int32
+
+const (
+	// BreakerClosed means the node is healthy and serves requests
+	BreakerClosed BreakerState = iota
+	// BreakerOpen means the node is bad, no request is sent to it until a probe succeeds
+	BreakerOpen
+	// BreakerHalfOpen means a probe request is being sent to the node
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// NodeEvent is emitted when a node goes down (breaker opens) or comes back up (breaker closes)
+type NodeEvent struct {
+	URL   string
+	State BreakerState
+	// Err is the error which opened the breaker, nil when the node comes back up
+	Err  StdError
+	Time time.Time
+}
+
+// IsUp return whether the node is healthy after the event
+func (e NodeEvent) IsUp() bool {
+	return e.State == BreakerClosed
+}
+
+// circuitBreaker holds the health state of a node
+type circuitBreaker struct {
+	state    int32
+	failures int32
+	probing  int32
+}
+
+func (cb *circuitBreaker) getState() BreakerState {
+	return BreakerState(atomic.LoadInt32(&cb.state))
+}
+
+func (cb *circuitBreaker) setState(state BreakerState) {
+	atomic.StoreInt32(&cb.state, int32(state))
+}
+
+func (cb *circuitBreaker) transfer(from, to BreakerState) bool {
+	return atomic.CompareAndSwapInt32(&cb.state, int32(from), int32(to))
+}
+
+// healthChecker records request results of nodes, opens the breaker of a failing node
+// and runs one prober per bad node until it recovers or the checker is stopped
+type healthChecker struct {
+	client           *http.Client
+	namespace        string
+	failureThreshold int32
+
+	// intervalMutex guards the probe intervals, which are set by RPC while probers are running
+	intervalMutex    sync.RWMutex
+	probeInterval    time.Duration
+	maxProbeInterval time.Duration
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	rwMutex   sync.RWMutex
+	stopped   bool
+	handlers  []func(NodeEvent)
+	listeners []chan NodeEvent
+}
+
+func newHealthChecker(client *http.Client, namespace string, failureThreshold, probeInterval, reConnTime int64) *healthChecker {
+	hc := &healthChecker{
+		client:    client,
+		namespace: namespace,
+		stopCh:    make(chan struct{}),
+	}
+	hc.setFailureThreshold(failureThreshold)
+	hc.setProbeInterval(probeInterval, reConnTime)
+	return hc
+}
+
+func (hc *healthChecker) setFailureThreshold(failureThreshold int64) {
+	if failureThreshold <= 0 {
+		failureThreshold = DefaultFailureThreshold
+	}
+	atomic.StoreInt32(&hc.failureThreshold, int32(failureThreshold))
+}
+
+// setProbeInterval sets the first and the max interval(ms) of probes, running probers use them from the next probe
+func (hc *healthChecker) setProbeInterval(probeInterval, reConnTime int64) {
+	hc.intervalMutex.Lock()
+	defer hc.intervalMutex.Unlock()
+	hc.updateProbeInterval(probeInterval, reConnTime)
+}
+
+// setReConnTime sets the max interval(ms) of probes and keeps the first one
+func (hc *healthChecker) setReConnTime(reConnTime int64) {
+	hc.intervalMutex.Lock()
+	defer hc.intervalMutex.Unlock()
+	hc.updateProbeInterval(int64(hc.probeInterval/time.Millisecond), reConnTime)
+}
+
+// updateProbeInterval must be called with intervalMutex held
+func (hc *healthChecker) updateProbeInterval(probeInterval, reConnTime int64) {
+	if probeInterval <= 0 {
+		probeInterval = DefaultProbeInterval
+	}
+	if reConnTime <= 0 {
+		reConnTime = DefaultReConnectTime
+	}
+	if reConnTime < probeInterval {
+		reConnTime = probeInterval
+	}
+	hc.probeInterval = time.Duration(probeInterval) * time.Millisecond
+	hc.maxProbeInterval = time.Duration(reConnTime) * time.Millisecond
+}
+
+// onSuccess is called when node responds
+func (hc *healthChecker) onSuccess(node *Node) {
+	if node.breaker.getState() == BreakerClosed {
+		atomic.StoreInt32(&node.breaker.failures, 0)
+	}
+}
+
+// onFailure is called when request to node fails for the node's fault
+func (hc *healthChecker) onFailure(node *Node, err StdError) {
+	if node.breaker.getState() != BreakerClosed {
+		return
+	}
+	if atomic.AddInt32(&node.breaker.failures, 1) >= atomic.LoadInt32(&hc.failureThreshold) {
+		hc.trip(node, err)
+	}
+}
+
+// trip opens the breaker of node and starts its prober
+func (hc *healthChecker) trip(node *Node, err StdError) {
+	if !node.breaker.transfer(BreakerClosed, BreakerOpen) {
+		return
+	}
+	logger.Warning("node " + node.url + " is bad, stop sending request to it")
+	hc.emit(NodeEvent{URL: node.url, State: BreakerOpen, Err: err, Time: time.Now()})
+	hc.startProber(node)
+}
+
+// startProber starts the prober of node if there is none
+func (hc *healthChecker) startProber(node *Node) {
+	hc.rwMutex.RLock()
+	defer hc.rwMutex.RUnlock()
+	if hc.stopped {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&node.breaker.probing, 0, 1) {
+		return
+	}
+	hc.wg.Add(1)
+	go hc.probeLoop(node)
+}
+
+func (hc *healthChecker) probeLoop(node *Node) {
+	defer hc.wg.Done()
+	defer atomic.StoreInt32(&node.breaker.probing, 0)
+
+	for attempt := 0; ; attempt++ {
+		timer := time.NewTimer(hc.backoff(attempt))
+		select {
+		case <-hc.stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !node.breaker.transfer(BreakerOpen, BreakerHalfOpen) {
+			// closed by others
+			return
+		}
+		if err := hc.probe(node); err != nil {
+			node.breaker.setState(BreakerOpen)
+			logger.Info("node " + node.url + " reconnect failed: " + err.Error())
+			continue
+		}
+		atomic.StoreInt32(&node.breaker.failures, 0)
+		node.breaker.setState(BreakerClosed)
+		logger.Info("node " + node.url + " Reconnect Success!")
+		hc.emit(NodeEvent{URL: node.url, State: BreakerClosed, Time: time.Now()})
+		return
+	}
+}
+
+// backoff return the wait time before the attempt-th probe,
+// it grows exponentially up to maxProbeInterval with jitter in [d/2, d)
+func (hc *healthChecker) backoff(attempt int) time.Duration {
+	hc.intervalMutex.RLock()
+	d, maxInterval := hc.probeInterval, hc.maxProbeInterval
+	hc.intervalMutex.RUnlock()
+	for i := 0; i < attempt && d < maxInterval; i++ {
+		d *= 2
+	}
+	if d > maxInterval {
+		d = maxInterval
+	}
+	half := d / 2
+	return half + time.Duration(common.RandInt(int(half/time.Millisecond)+1))*time.Millisecond
+}
+
+// probe sends node_getNodes to node, the request is aborted when checker is stopped
+func (hc *healthChecker) probe(node *Node) error {
+	body, err := json.Marshal(&JSONRequest{
+		Method:    NODE + "getNodes",
+		Version:   JSONRPCVersion,
+		ID:        1,
+		Namespace: hc.namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-hc.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, stdErr := postWithContext(ctx, node.url, body)
+	if stdErr != nil {
+		return stdErr
+	}
+	resp, err := hc.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("http failed " + resp.Status)
+	}
+	logger.Debug("reconnection node body: ", string(b))
+	return nil
+}
+
+// subscribe register handler which is called synchronously on every node event
+func (hc *healthChecker) subscribe(handler func(NodeEvent)) {
+	hc.rwMutex.Lock()
+	defer hc.rwMutex.Unlock()
+	hc.handlers = append(hc.handlers, handler)
+}
+
+// listen return a channel receiving node events, events are dropped when the channel is full,
+// the channel is closed when checker is stopped
+func (hc *healthChecker) listen(buffer int) <-chan NodeEvent {
+	ch := make(chan NodeEvent, buffer)
+	hc.rwMutex.Lock()
+	defer hc.rwMutex.Unlock()
+	if hc.stopped {
+		close(ch)
+		return ch
+	}
+	hc.listeners = append(hc.listeners, ch)
+	return ch
+}
+
+func (hc *healthChecker) emit(event NodeEvent) {
+	hc.rwMutex.RLock()
+	handlers := hc.handlers
+	hc.rwMutex.RUnlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+
+	hc.rwMutex.RLock()
+	defer hc.rwMutex.RUnlock()
+	if hc.stopped {
+		return
+	}
+	for _, ch := range hc.listeners {
+		select {
+		case ch <- event:
+		default:
+			logger.Warningf("node event channel is full, drop event of %s", event.URL)
+		}
+	}
+}
+
+// stop stops all probers and closes the event channels
+func (hc *healthChecker) stop() {
+	hc.stopOnce.Do(func() {
+		hc.rwMutex.Lock()
+		hc.stopped = true
+		close(hc.stopCh)
+		hc.rwMutex.Unlock()
+
+		hc.wg.Wait()
+
+		hc.rwMutex.Lock()
+		for _, ch := range hc.listeners {
+			close(ch)
+		}
+		hc.listeners = nil
+		hc.rwMutex.Unlock()
+	})
+}
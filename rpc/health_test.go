@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthChecker_Backoff(t *testing.T) {
+	hc := newHealthChecker(nil, DefaultNamespace, 1, 100, 1000)
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			d := hc.backoff(attempt)
+			assert.True(t, d >= max/2 && d <= max, "attempt %d: %v", attempt, d)
+		}
+	}
+}
+
+func TestHealthChecker_NodeDownAndUp(t *testing.T) {
+	var fail int32 = 1
+	rp, closeFn := newTestRPC(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"code":0,"result":[]}`))
+	})
+	defer closeFn()
+	rp.FailureThreshold(2).ProbeInterval(10).ReConnTime(40)
+	events := rp.NodeEvents(4)
+	var handled int32
+	rp.OnNodeStateChange(func(NodeEvent) {
+		atomic.AddInt32(&handled, 1)
+	})
+	node := rp.hrm.nodes[0]
+
+	_, err := rp.GetNodes()
+	assert.NotNil(t, err)
+	assert.Equal(t, BreakerClosed, node.State())
+
+	_, err = rp.GetNodes()
+	assert.NotNil(t, err)
+	assert.NotEqual(t, BreakerClosed, node.State())
+
+	// no node is available
+	_, err = rp.GetNodes()
+	assert.Equal(t, GetResponseErrorCode, err.Code())
+
+	event := <-events
+	assert.False(t, event.IsUp())
+	assert.Equal(t, node.url, event.URL)
+	assert.NotNil(t, event.Err)
+
+	atomic.StoreInt32(&fail, 0)
+	select {
+	case event = <-events:
+		assert.True(t, event.IsUp())
+	case <-time.After(2 * time.Second):
+		t.Fatal("node is not reconnected")
+	}
+	assert.Equal(t, BreakerClosed, node.State())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&handled))
+
+	nodes, err := rp.GetNodes()
+	assert.Nil(t, err)
+	assert.Len(t, nodes, 0)
+
+	rp.Close()
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestHealthChecker_StopProber(t *testing.T) {
+	var probes, inflight, maxInflight int32
+	probed := make(chan struct{}, 1)
+	rp, closeFn := newTestRPC(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for m := atomic.LoadInt32(&maxInflight); n > m; m = atomic.LoadInt32(&maxInflight) {
+			if atomic.CompareAndSwapInt32(&maxInflight, m, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&probes, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+		select {
+		case probed <- struct{}{}:
+		default:
+		}
+	})
+	defer closeFn()
+	rp.ProbeInterval(5).ReConnTime(10)
+
+	// only one prober runs for a node
+	rp.hrm.ReConnectNode(0)
+	rp.hrm.ReConnectNode(0)
+	rp.hrm.health.startProber(rp.hrm.nodes[0])
+	for i := 0; i < 3; i++ {
+		select {
+		case <-probed:
+		case <-time.After(time.Second):
+			t.Fatal("node is not probed")
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		rp.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("prober is not stopped")
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&rp.hrm.nodes[0].breaker.probing))
+	// the prober has exited, wait for the handler of its last probe which may be canceled
+	closeFn()
+	assert.True(t, atomic.LoadInt32(&probes) >= 3)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxInflight))
+	assert.Equal(t, BreakerOpen, rp.hrm.nodes[0].State())
+}
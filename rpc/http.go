@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -13,9 +14,12 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperchain/gosdk/common"
+	"github.com/opentracing/opentracing-go"
+	"github.com/spf13/cast"
 	"github.com/terasum/viper"
 )
 
@@ -29,9 +33,41 @@ const (
 
 // Node is used to contain node info
 type Node struct {
-	url    string
-	wsURL  string
-	status bool
+	url     string
+	wsURL   string
+	weight  int32
+	breaker *circuitBreaker
+}
+
+// isAvailable return whether the node can serve requests, that is its breaker is closed
+func (n *Node) isAvailable() bool {
+	return n.breaker.getState() == BreakerClosed
+}
+
+// setAvailable force the breaker state of node without probing
+func (n *Node) setAvailable(available bool) {
+	if available {
+		n.breaker.setState(BreakerClosed)
+	} else {
+		n.breaker.setState(BreakerOpen)
+	}
+}
+
+// State return the breaker state of node
+func (n *Node) State() BreakerState {
+	return n.breaker.getState()
+}
+
+// getWeight return the weight used by weighted selector, at least 1
+func (n *Node) getWeight() int {
+	if w := atomic.LoadInt32(&n.weight); w > 1 {
+		return int(w)
+	}
+	return 1
+}
+
+func (n *Node) setWeight(weight int) {
+	atomic.StoreInt32(&n.weight, int32(weight))
 }
 
 func newNode(url string, rpcPort string, wsPort string, isHTTPS bool) (node *Node) {
@@ -44,9 +80,13 @@ func newNode(url string, rpcPort string, wsPort string, isHTTPS bool) (node *Nod
 	}
 
 	node = &Node{
-		url:    scheme + url + ":" + rpcPort,
-		wsURL:  "ws://" + url + ":" + wsPort,
-		status: true,
+		url:     scheme + url + ":" + rpcPort,
+		weight:  1,
+		breaker: &circuitBreaker{},
+	}
+	// websocket is not available if its port is not configured
+	if wsPort != "" {
+		node.wsURL = "ws://" + url + ":" + wsPort
 	}
 	return node
 }
@@ -59,14 +99,21 @@ func NewNode(url string, rpcPort string, wsPort string) (node *Node) {
 // httpRequestManager is used to manager node and http request
 type httpRequestManager struct {
 	nodes      []*Node
-	nodeIndex  int
+	selector   NodeSelector
 	client     *http.Client
 	namespace  string
 	sendTcert  bool
 	isHTTP     bool
 	tcm        *TCertManager
 	reConnTime int64
+	health     *healthChecker
 	txVersion  string
+	// interceptors applied to every request, inspector authentication is the first one
+	interceptors []Interceptor
+	metrics      MetricsSink
+	// metricsSubscribed is whether node events are reported to metrics
+	metricsSubscribed bool
+	tracer            opentracing.Tracer
 }
 
 // newHTTPRequestManager is used to construct httpRequestManager
@@ -95,29 +142,48 @@ func newHTTPRequestManager2(vip *viper.Viper, txVersion string) (hrm *httpReques
 	logger.Debugf("[CONFIG]: %s = %v", common.SecurityHttps, isHTTPS)
 
 	reConnTime := vip.GetInt64(common.ReConnectTime)
+	failureThreshold := vip.GetInt64(common.HealthFailureThreshold)
+	logger.Debugf("[CONFIG]: %s = %v", common.HealthFailureThreshold, failureThreshold)
+	probeInterval := vip.GetInt64(common.HealthProbeInterval)
+	logger.Debugf("[CONFIG]: %s = %v", common.HealthProbeInterval, probeInterval)
 
 	var nodes = make([]*Node, len(urls))
 
 	for i, url := range urls {
-		nodes[i] = newNode(url, rpcPorts[i], wsPorts[i], isHTTPS)
+		var wsPort string
+		if i < len(wsPorts) {
+			wsPort = wsPorts[i]
+		}
+		nodes[i] = newNode(url, rpcPorts[i], wsPort, isHTTPS)
 	}
 
+	weights := cast.ToIntSlice(vip.Get(common.JSONRPCWeights))
+	logger.Debugf("[CONFIG]: %s = %v", common.JSONRPCWeights, weights)
+	for i := 0; i < len(weights) && i < len(nodes); i++ {
+		nodes[i].setWeight(weights[i])
+	}
+
+	selector := vip.GetString(common.JSONRPCSelector)
+	logger.Debugf("[CONFIG]: %s = %v", common.JSONRPCSelector, selector)
+
 	sendTcert := vip.GetBool(common.PrivacySendTcert)
 	logger.Debugf("[CONFIG]: sendTcert = %v", sendTcert)
 
 	tcm = NewTCertManager2(vip)
+	client := newHTTPClient2(vip)
 
 	fmt.Println("tcm:", tcm)
 
 	httpRequestManager := &httpRequestManager{
 		nodes:      nodes,
-		nodeIndex:  0,
-		client:     newHTTPClient2(vip),
+		selector:   newNodeSelector(selector),
+		client:     client,
 		namespace:  namespace,
 		sendTcert:  sendTcert,
 		tcm:        tcm,
 		isHTTP:     isHTTPS,
 		reConnTime: reConnTime,
+		health:     newHealthChecker(client, namespace, failureThreshold, probeInterval, reConnTime),
 		txVersion:  txVersion,
 	}
 
@@ -166,27 +232,46 @@ func newHTTPRequestManager(vip *viper.Viper, confRootPath string, txVersion stri
 	logger.Debugf("[CONFIG]: %s = %v", common.SecurityHttps, isHTTPS)
 
 	reConnTime := vip.GetInt64(common.ReConnectTime)
+	failureThreshold := vip.GetInt64(common.HealthFailureThreshold)
+	logger.Debugf("[CONFIG]: %s = %v", common.HealthFailureThreshold, failureThreshold)
+	probeInterval := vip.GetInt64(common.HealthProbeInterval)
+	logger.Debugf("[CONFIG]: %s = %v", common.HealthProbeInterval, probeInterval)
 
 	var nodes = make([]*Node, len(urls))
 
 	for i, url := range urls {
-		nodes[i] = newNode(url, rpcPorts[i], wsPorts[i], isHTTPS)
+		var wsPort string
+		if i < len(wsPorts) {
+			wsPort = wsPorts[i]
+		}
+		nodes[i] = newNode(url, rpcPorts[i], wsPort, isHTTPS)
+	}
+
+	weights := cast.ToIntSlice(vip.Get(common.JSONRPCWeights))
+	logger.Debugf("[CONFIG]: %s = %v", common.JSONRPCWeights, weights)
+	for i := 0; i < len(weights) && i < len(nodes); i++ {
+		nodes[i].setWeight(weights[i])
 	}
 
+	selector := vip.GetString(common.JSONRPCSelector)
+	logger.Debugf("[CONFIG]: %s = %v", common.JSONRPCSelector, selector)
+
 	sendTcert := vip.GetBool(common.PrivacySendTcert)
 	logger.Debugf("[CONFIG]: sendTcert = %v", sendTcert)
 
 	tcm = NewTCertManager(vip, confRootPath)
+	client := newHTTPClient(vip, confRootPath)
 
 	httpRequestManager := &httpRequestManager{
 		nodes:      nodes,
-		nodeIndex:  0,
-		client:     newHTTPClient(vip, confRootPath),
+		selector:   newNodeSelector(selector),
+		client:     client,
 		namespace:  namespace,
 		sendTcert:  sendTcert,
 		tcm:        tcm,
 		isHTTP:     isHTTPS,
 		reConnTime: reConnTime,
+		health:     newHealthChecker(client, namespace, failureThreshold, probeInterval, reConnTime),
 		txVersion:  txVersion,
 	}
 
@@ -305,102 +390,138 @@ func newHTTPClient(vip *viper.Viper, confRootPath string) *http.Client {
 }
 
 func defaultHTTPRequestManager() *httpRequestManager {
+	client := &http.Client{}
 	return &httpRequestManager{
-		namespace: DefaultNamespace,
-		nodes:     make([]*Node, 0),
-		nodeIndex: 0,
-		client:    &http.Client{},
-		sendTcert: false,
-		tcm:       nil,
-		isHTTP:    false,
+		namespace:  DefaultNamespace,
+		nodes:      make([]*Node, 0),
+		selector:   NewRandomSelector(),
+		client:     client,
+		sendTcert:  false,
+		tcm:        nil,
+		isHTTP:     false,
+		reConnTime: DefaultReConnectTime,
+		health:     newHealthChecker(client, DefaultNamespace, DefaultFailureThreshold, DefaultProbeInterval, DefaultReConnectTime),
 	}
 }
 
 func post(url string, body []byte) (*http.Request, StdError) {
-	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
-	return req, NewGetResponseError(err)
+	return postWithContext(context.Background(), url, body)
 }
 
-func addHeaders(req *http.Request, extraHeaders map[string]string) {
-	if extraHeaders != nil {
-		for k, v := range extraHeaders {
-			req.Header.Add(k, v)
-		}
-	}
+func postWithContext(ctx context.Context, url string, body []byte) (*http.Request, StdError) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	return req, NewGetResponseError(err)
 }
 
 // SyncRequest function is used to send http request
 func (hrm *httpRequestManager) SyncRequest(body []byte) ([]byte, StdError) {
-	randomURL, stdErr := hrm.randomURL()
-	if stdErr != nil {
-		return nil, stdErr
-	}
+	return hrm.SyncRequestWithContext(context.Background(), body)
+}
 
-	return hrm.SyncRequestSpecificURL(body, randomURL, GENERAL, nil, nil)
+// SyncRequestWithContext send http request to the node chosen by selector, the request is aborted when ctx is done
+func (hrm *httpRequestManager) SyncRequestWithContext(ctx context.Context, body []byte) ([]byte, StdError) {
+	return hrm.SyncRequestSpecificURLWithContext(ctx, body, "", GENERAL, nil, nil)
 }
 
 // SyncRequestSpecificURL is used to post request to specific url
 func (hrm *httpRequestManager) SyncRequestSpecificURL(body []byte, url string, requestType RequestType, extraHeaders map[string]string, rwSeeker io.ReadWriteSeeker) ([]byte, StdError) {
-	var req *http.Request
-	var stdErr StdError
-	switch requestType {
-	case DOWNLOAD:
-		req, stdErr = post(url, body)
+	return hrm.SyncRequestSpecificURLWithContext(context.Background(), body, url, requestType, extraHeaders, rwSeeker)
+}
+
+// SyncRequestSpecificURLWithContext is used to post request to specific url, the request is aborted when ctx is done.
+// The node is chosen by selector if url is empty.
+func (hrm *httpRequestManager) SyncRequestSpecificURLWithContext(ctx context.Context, body []byte, url string, requestType RequestType, extraHeaders map[string]string, rwSeeker io.ReadWriteSeeker) ([]byte, StdError) {
+	call := &Call{
+		URL:    url,
+		Header: make(http.Header),
+		body:   body,
+	}
+	for k, v := range extraHeaders {
+		call.Header.Add(k, v)
+	}
+	if err := hrm.do(ctx, call, requestType, rwSeeker); err != nil {
+		return nil, err
+	}
+	return call.Response, nil
+}
+
+// do runs call through the interceptors and posts it to call.URL, the response body is set to call.Response
+func (hrm *httpRequestManager) do(ctx context.Context, call *Call, requestType RequestType, rwSeeker io.ReadWriteSeeker) StdError {
+	if call.Header == nil {
+		call.Header = make(http.Header)
+	}
+	if call.URL == "" {
+		url, stdErr := hrm.selectURL(call.routeKey())
 		if stdErr != nil {
-			return nil, stdErr
+			return stdErr
 		}
-		addHeaders(req, extraHeaders)
+		call.URL = url
+	}
+	return hrm.intercept(ctx, call, func(ctx context.Context, call *Call) StdError {
+		return hrm.send(ctx, call, requestType, rwSeeker)
+	})
+}
+
+// send posts call to call.URL
+func (hrm *httpRequestManager) send(ctx context.Context, call *Call, requestType RequestType, rwSeeker io.ReadWriteSeeker) StdError {
+	if ctx.Err() != nil {
+		return NewRequestCanceledError(ctx.Err())
+	}
+
+	url := call.URL
+	body, stdErr := call.Body()
+	if stdErr != nil {
+		return stdErr
+	}
+	var req *http.Request
+	switch requestType {
 	case UPLOAD:
 		var err error
-		req, err = http.NewRequest("POST", url, rwSeeker)
+		req, err = http.NewRequestWithContext(ctx, "POST", url, rwSeeker)
 		if err != nil {
-			return nil, NewSystemError(err)
+			return NewSystemError(err)
 		}
-		addHeaders(req, extraHeaders)
-	case GENERAL:
+	case DOWNLOAD, GENERAL:
 		fallthrough
 	default:
-		req, stdErr = post(url, body)
+		req, stdErr = postWithContext(ctx, url, body)
 		if stdErr != nil {
-			return nil, stdErr
+			return stdErr
 		}
 	}
-
-	if hrm.sendTcert {
-		if isFlato(hrm.txVersion) || hrm.tcm.cfca {
-			signature, sysErr := hrm.tcm.sdkCert.Sign(body)
-			if sysErr != nil {
-				logger.Error("sign error", sysErr)
-				return nil, NewSystemError(sysErr)
-			}
-			req.Header.Add("tcert", hrm.tcm.ecert)
-			req.Header.Add("signature", common.Bytes2Hex(signature))
-			req.Header.Add("msg", common.Bytes2Hex(body))
-		} else {
-			signature, err := hrm.tcm.uniqueCert.Sign(body)
-			if err != nil {
-				logger.Error("signature body error,", err)
-				return nil, NewSystemError(err)
-			}
-			req.Header.Add("tcert", string(hrm.tcm.tcertPool[url]))
-			req.Header.Add("signature", common.Bytes2Hex(signature))
-			req.Header.Add("msg", common.Bytes2Hex(body))
-		}
+	for k, v := range call.Header {
+		req.Header[k] = append(req.Header[k], v...)
+	}
+	// file requests carry the json rpc request in header
+	if requestType != GENERAL && call.Request != nil {
+		req.Header.Set("params", string(body))
 	}
 
 	logger.Debug("[URL]:", url)
 	logger.Debug("[REQUEST]:", string(body))
 
+	start := time.Now()
 	resp, sysErr := hrm.client.Do(req)
 	if sysErr != nil {
-		return nil, NewGetResponseError(sysErr)
+		if ctx.Err() != nil {
+			return NewRequestCanceledError(ctx.Err())
+		}
+		stdErr = NewGetResponseError(sysErr)
+		hrm.observe(url, time.Since(start), stdErr, true)
+		return stdErr
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusOK {
+		hrm.observe(url, time.Since(start), nil, false)
+	} else {
+		hrm.observe(url, time.Since(start), NewHttpResponseError(resp.StatusCode, resp.Status), isTemporaryError(resp.StatusCode))
+	}
+
 	if resp.StatusCode == http.StatusOK {
 		// 200
 		if requestType == DOWNLOAD && resp.Header.Get("Content-Type") == "application/octet-stream" {
-			strPos := extraHeaders["pos"]
+			strPos := call.Header.Get("pos")
 			var pos int64
 			if strPos != "" {
 				var err error
@@ -411,25 +532,30 @@ func (hrm *httpRequestManager) SyncRequestSpecificURL(body []byte, url string, r
 			}
 			fsErr := streamFileStorage(rwSeeker, resp.Body, pos)
 			if fsErr != nil {
-				return nil, NewSystemError(fsErr)
+				if ctx.Err() != nil {
+					return NewRequestCanceledError(ctx.Err())
+				}
+				return NewSystemError(fsErr)
 			}
-			return newFakeJSONResponse(0, "download success", hrm.txVersion), nil
+			call.Response = newFakeJSONResponse(0, "download success", hrm.txVersion)
+			return nil
 		} else {
 			ret, err := ioutil.ReadAll(resp.Body)
 			if err != nil {
-				return nil, NewSystemError(err)
+				if ctx.Err() != nil {
+					return NewRequestCanceledError(ctx.Err())
+				}
+				return NewSystemError(err)
 			}
 			logger.Debug("[RESPONSE]:", string(ret))
-			return ret, nil
+			call.Response = ret
+			return nil
 		}
 	} else if !isTemporaryError(resp.StatusCode) {
-		return nil, NewHttpResponseError(resp.StatusCode, resp.Status)
+		return NewHttpResponseError(resp.StatusCode, resp.Status)
 	}
 
-	// 请求异常返回，重连节点
-	hrm.ReConnectNode(hrm.nodeIndex)
-
-	return nil, NewGetResponseError(errors.New("http failed " + resp.Status))
+	return NewGetResponseError(errors.New("http failed " + resp.Status))
 }
 
 func isTemporaryError(code int) bool {
@@ -575,27 +701,54 @@ func (hrm *httpRequestManager) getTCert(url string) (string, StdError) {
 	return "", NewGetResponseError(errors.New("http failed " + resp.Status))
 }
 
+// randomURL return the url of a node chosen by selector without routing key
 func (hrm *httpRequestManager) randomURL() (url string, err StdError) {
-	nodeNum := len(hrm.nodes)
-	randomNum := nodeNum * 2
-	for randomNum > 0 {
-		hrm.nodeIndex = common.RandInt(nodeNum)
-		if hrm.nodes[hrm.nodeIndex].status {
-			return hrm.nodes[hrm.nodeIndex].url, nil
-		}
-		randomNum--
+	return hrm.selectURL("")
+}
+
+// selectURL return the url of the node chosen by selector for the routing key
+func (hrm *httpRequestManager) selectURL(key string) (url string, err StdError) {
+	selector := hrm.selector
+	if selector == nil {
+		selector = NewRandomSelector()
 	}
-	logger.Error("All nodes are bad, please check it! Now retry to connect all nodes.")
+	index := selector.Select(hrm.nodes, key)
+	if index < 0 || index >= len(hrm.nodes) {
+		logger.Error("All nodes are bad, please check it!")
+		return "", NewGetResponseError(errors.New("all nodes are bad, please check it"))
+	}
+	return hrm.nodes[index].url, nil
+}
 
-	//if random fail, try round
-	for i := 0; i < nodeNum; i++ {
-		hrm.nodeIndex = (hrm.nodeIndex + 1) % nodeNum
-		if hrm.nodes[hrm.nodeIndex].status {
-			return hrm.nodes[hrm.nodeIndex].url, nil
+// indexOfURL return the index of the node with url, -1 if not found
+func (hrm *httpRequestManager) indexOfURL(url string) int {
+	for i, node := range hrm.nodes {
+		if node.url == url {
+			return i
 		}
 	}
+	return -1
+}
 
-	return "", NewGetResponseError(errors.New("all nodes are bad, please check it"))
+// observe report the result of request to url to selector and health checker,
+// nodeFault indicates that the node failed to serve the request
+func (hrm *httpRequestManager) observe(url string, rt time.Duration, err StdError, nodeFault bool) {
+	index := hrm.indexOfURL(url)
+	if index < 0 {
+		return
+	}
+	node := hrm.nodes[index]
+	if hrm.selector != nil {
+		hrm.selector.Observe(node, rt, err)
+	}
+	if hrm.health == nil {
+		return
+	}
+	if nodeFault {
+		hrm.health.onFailure(node, err)
+	} else {
+		hrm.health.onSuccess(node)
+	}
 }
 
 // getNodeURL get the url of the node
@@ -606,53 +759,15 @@ func (hrm *httpRequestManager) getNodeURL(nodeID int) (url string, err StdError)
 	if nodeID == 0 {
 		return hrm.randomURL()
 	}
-	if !hrm.nodes[nodeID-1].status {
+	if !hrm.nodes[nodeID-1].isAvailable() {
 		return "", NewGetResponseError(errors.New(fmt.Sprintf("node %d is bad, please check it", nodeID)))
 	}
 	return hrm.nodes[nodeID-1].url, nil
 }
 
-// ReConnectNode is used to reconnect the node by index
+// ReConnectNode is used to mark the node by index as bad and reconnect it in background
 func (hrm *httpRequestManager) ReConnectNode(nodeIndex int) {
-	hrm.nodes[nodeIndex].status = false
-	url := hrm.nodes[nodeIndex].url
-	req := &JSONRequest{
-		Method:    "node_getNodes",
-		Version:   JSONRPCVersion,
-		ID:        1,
-		Namespace: hrm.namespace,
-	}
-	body, err := json.Marshal(req)
-	if err != nil {
-		logger.Error(NewSystemError(err).String())
-	}
-
-	go func() {
-		request, err := post(url, body)
-		if err != nil {
-			logger.Error(err.String())
-		}
-
-		for {
-			response, err := hrm.client.Do(request)
-			if err != nil {
-				logger.Error(NewSystemError(err).String())
-			}
-
-			if response != nil && response.StatusCode == http.StatusOK {
-				b, _ := ioutil.ReadAll(response.Body)
-				logger.Debug("reconnection node body: ", string(b))
-				response.Body.Close()
-				hrm.nodes[nodeIndex].status = true
-				logger.Info("node " + hrm.nodes[nodeIndex].url + " Reconnect Success!")
-				return
-			}
-			response.Body.Close()
-			logger.Info("node " + hrm.nodes[nodeIndex].url + " Reconnect failed, will try one second later")
-			time.Sleep(time.Millisecond * time.Duration(hrm.reConnTime))
-		}
-	}()
-
+	hrm.health.trip(hrm.nodes[nodeIndex], nil)
 }
 
 func isFlato(TxVersion string) bool {
@@ -13,7 +13,7 @@ func TestRPC_Reconnect(t *testing.T) {
 
 func TestRPC_GetNode(t *testing.T) {
 	rpc := NewRPC()
-	rpc.hrm.nodes[0].status = false
+	rpc.hrm.nodes[0].setAvailable(false)
 	fmt.Print(rpc.GetNodes())
 
 }
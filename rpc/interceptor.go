@@ -0,0 +1,246 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hyperchain/gosdk/common"
+)
+
+// Call is a json rpc call passing through the interceptor chain
+type Call struct {
+	// Request is the json rpc request, it is nil for batch call or call made with serialized body
+	Request *JSONRequest
+	// Batch is the requests of batch call
+	Batch []*JSONRequest
+	// URL is the url of the node the call is sent to, it is chosen before the chain is called.
+	// An interceptor may change it, or set it to empty to choose a node again, before calling next.
+	URL string
+	// Header is the header of the http request, it is nil if the call is not sent over http
+	Header http.Header
+	// Response is the raw response, it is set when the call succeeds
+	Response []byte
+
+	body []byte
+}
+
+// Handler sends the call and sets call.Response
+type Handler func(ctx context.Context, call *Call) StdError
+
+// Interceptor is a middleware around sending a call, it can modify the call before calling next,
+// inspect call.Response and the error after next returns, short-circuit by not calling next,
+// or retry by calling next again.
+type Interceptor func(ctx context.Context, call *Call, next Handler) StdError
+
+// Use append interceptors to the chain, the first one is the outermost.
+// Interceptors are called after inspector authentication and before tcert signing,
+// they apply to calls of rpc and the instances derived from it afterwards.
+func (rpc *RPC) Use(interceptors ...Interceptor) *RPC {
+	chain := make([]Interceptor, 0, len(rpc.hrm.interceptors)+len(interceptors))
+	chain = append(chain, rpc.hrm.interceptors...)
+	rpc.hrm.interceptors = append(chain, interceptors...)
+	return rpc
+}
+
+// Requests return the json rpc requests carried by the call
+func (c *Call) Requests() []*JSONRequest {
+	if c.Request != nil {
+		return []*JSONRequest{c.Request}
+	}
+	return c.Batch
+}
+
+// Body return the serialized request, it changes if Request or Batch is modified
+func (c *Call) Body() ([]byte, StdError) {
+	var (
+		body   []byte
+		sysErr error
+	)
+	switch {
+	case c.Request != nil:
+		body, sysErr = json.Marshal(c.Request)
+	case c.Batch != nil:
+		body, sysErr = json.Marshal(c.Batch)
+	default:
+		return c.body, nil
+	}
+	if sysErr != nil {
+		return nil, NewSystemError(sysErr)
+	}
+	return body, nil
+}
+
+// result return the result of the response, a response with non-zero code is returned as ServerError
+func (c *Call) result() (json.RawMessage, StdError) {
+	var resp *JSONResponse
+	if sysErr := json.Unmarshal(c.Response, &resp); sysErr != nil {
+		return nil, NewSystemError(sysErr)
+	}
+	if resp.Code != SuccessCode {
+		return nil, withRequest(NewServerError(resp.Code, resp.Message), c)
+	}
+	return resp.Result, nil
+}
+
+// routeKey return the routing key of the call for node selector
+func (c *Call) routeKey() string {
+	if requests := c.Requests(); len(requests) > 0 {
+		return routeKey(requests[0])
+	}
+	return ""
+}
+
+// intercept calls handler through the chain: the interceptors of hrm, routing, tracing, metrics and tcert signing
+func (hrm *httpRequestManager) intercept(ctx context.Context, call *Call, handler Handler) StdError {
+	chain := make([]Interceptor, 0, len(hrm.interceptors)+4)
+	chain = append(chain, hrm.interceptors...)
+	chain = append(chain, hrm.routeInterceptor)
+	if hrm.tracer != nil {
+		chain = append(chain, hrm.tracingInterceptor)
+	}
+	if hrm.metrics != nil {
+		chain = append(chain, hrm.metricsInterceptor)
+	}
+	chain = append(chain, hrm.tcertInterceptor)
+	for i := len(chain) - 1; i >= 0; i-- {
+		interceptor, next := chain[i], handler
+		handler = func(ctx context.Context, call *Call) StdError {
+			return interceptor(ctx, call, next)
+		}
+	}
+	if err := handler(ctx, call); err != nil {
+		return withRequest(err, call)
+	}
+	return nil
+}
+
+// routeInterceptor chooses a node again if an interceptor cleared call.URL
+func (hrm *httpRequestManager) routeInterceptor(ctx context.Context, call *Call, next Handler) StdError {
+	if call.URL == "" {
+		url, stdErr := hrm.selectURL(call.routeKey())
+		if stdErr != nil {
+			return stdErr
+		}
+		call.URL = url
+	}
+	return next(ctx, call)
+}
+
+// tcertInterceptor signs the body and adds tcert headers if sendTcert is enabled
+func (hrm *httpRequestManager) tcertInterceptor(ctx context.Context, call *Call, next Handler) StdError {
+	if !hrm.sendTcert || call.Header == nil {
+		return next(ctx, call)
+	}
+	body, stdErr := call.Body()
+	if stdErr != nil {
+		return stdErr
+	}
+	var (
+		signature []byte
+		tcert     string
+		err       error
+	)
+	if isFlato(hrm.txVersion) || hrm.tcm.cfca {
+		signature, err = hrm.tcm.sdkCert.Sign(body)
+		tcert = hrm.tcm.ecert
+	} else {
+		signature, err = hrm.tcm.uniqueCert.Sign(body)
+		tcert = string(hrm.tcm.tcertPool[call.URL])
+	}
+	if err != nil {
+		logger.Error("signature body error,", err)
+		return NewSystemError(err)
+	}
+	call.Header.Set("tcert", tcert)
+	call.Header.Set("signature", common.Bytes2Hex(signature))
+	call.Header.Set("msg", common.Bytes2Hex(body))
+	return next(ctx, call)
+}
+
+// authInterceptor adds inspector authentication to the requests if inspector is enabled.
+// The body of a call made with serialized body is decoded to add it to the requests without it,
+// the authentication signed when the body is built, such as by TxRequestBody, is kept.
+func authInterceptor(im *inspectorManager) Interceptor {
+	return func(ctx context.Context, call *Call, next Handler) StdError {
+		if !im.enable {
+			return next(ctx, call)
+		}
+		requests := call.Requests()
+		if len(requests) == 0 {
+			body, stdErr := im.authBody(call.body)
+			if stdErr != nil {
+				return stdErr
+			}
+			call.body = body
+		}
+		for _, req := range requests {
+			req.Auth = im.authentication()
+		}
+		return next(ctx, call)
+	}
+}
+
+// authentication return the inspector authentication signed now
+func (im *inspectorManager) authentication() *Authentication {
+	auth := &Authentication{
+		Address:   im.key.GetAddress(),
+		Timestamp: time.Now().UnixNano(),
+	}
+	sig, err := sign(im.key, authNeedHash(auth), false)
+	if err != nil {
+		logger.Errorf("sign auth fail")
+	}
+	auth.Signature = sig
+	return auth
+}
+
+// authBody adds inspector authentication to the requests without it in body, which is a serialized request
+// or batch, an error is returned if it is neither, rather than sending it without authentication
+func (im *inspectorManager) authBody(body []byte) ([]byte, StdError) {
+	var (
+		requests []map[string]json.RawMessage
+		trimmed  = bytes.TrimSpace(body)
+		batch    = len(trimmed) > 0 && trimmed[0] == '['
+		err      error
+	)
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		requests = make([]map[string]json.RawMessage, 1)
+		err = json.Unmarshal(body, &requests[0])
+	}
+	if err != nil {
+		return nil, NewSystemError(fmt.Errorf("add inspector authentication to body: %v", err))
+	}
+
+	signed := false
+	for _, req := range requests {
+		if req == nil {
+			return nil, NewSystemError(errors.New("add inspector authentication to body: not a json rpc request"))
+		}
+		if auth, ok := req["auth"]; ok && string(auth) != "null" {
+			continue
+		}
+		if req["auth"], err = json.Marshal(im.authentication()); err != nil {
+			return nil, NewSystemError(err)
+		}
+		signed = true
+	}
+	if !signed {
+		return body, nil
+	}
+	if batch {
+		body, err = json.Marshal(requests)
+	} else {
+		body, err = json.Marshal(requests[0])
+	}
+	if err != nil {
+		return nil, NewSystemError(err)
+	}
+	return body, nil
+}
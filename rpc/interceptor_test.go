@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptor_Order(t *testing.T) {
+	rp, _, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	var trace []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, call *Call, next Handler) StdError {
+			trace = append(trace, name+" before "+call.Request.Method)
+			assert.NotEmpty(t, call.URL)
+			err := next(ctx, call)
+			assert.NotEmpty(t, call.Response)
+			trace = append(trace, name+" after")
+			return err
+		}
+	}
+	rp.Use(record("first")).Use(record("second"))
+
+	_, err := rp.GetNodes()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"first before " + NODE + "getNodes",
+		"second before " + NODE + "getNodes",
+		"second after",
+		"first after",
+	}, trace)
+}
+
+func TestInterceptor_ShortCircuit(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	rp.Use(func(ctx context.Context, call *Call, next Handler) StdError {
+		if call.Request.Method == NODE+"getNodes" {
+			return NewSystemError(assert.AnError)
+		}
+		if call.Request.Method == BLOCK+"getChainHeight" {
+			call.Response, _ = json.Marshal(&JSONResponse{Result: json.RawMessage(`"0x10"`)})
+			return nil
+		}
+		return next(ctx, call)
+	})
+
+	_, err := rp.GetNodes()
+	assert.Equal(t, SystemErrorCode, err.Code())
+	height, err := rp.GetChainHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, "0x10", height)
+	assert.Equal(t, 0, srv.Requests(NODE+"getNodes"))
+	assert.Equal(t, 0, srv.Requests(BLOCK+"getChainHeight"))
+}
+
+func TestInterceptor_Retry(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:  NODE + "getNodes",
+		Times:   1,
+		Code:    rpctest.DataNotExistCode,
+		Message: "not ready",
+	})
+
+	var codes []int
+	rp.Use(func(ctx context.Context, call *Call, next Handler) StdError {
+		for {
+			if err := next(ctx, call); err != nil {
+				return err
+			}
+			var resp JSONResponse
+			if err := json.Unmarshal(call.Response, &resp); err != nil {
+				return NewSystemError(err)
+			}
+			codes = append(codes, resp.Code)
+			if resp.Code != DataNotExistCode {
+				return nil
+			}
+			// choose a node again
+			call.URL = ""
+		}
+	})
+
+	_, err := rp.GetNodes()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{DataNotExistCode, SuccessCode}, codes)
+	assert.Equal(t, 2, srv.Requests(NODE+"getNodes"))
+}
+
+func TestInterceptor_Batch(t *testing.T) {
+	rp, _, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	var methods []string
+	rp.Use(func(ctx context.Context, call *Call, next Handler) StdError {
+		for _, req := range call.Requests() {
+			methods = append(methods, req.Method)
+		}
+		return next(ctx, call)
+	})
+
+	results, err := rp.NewBatch().Add(BLOCK + "getChainHeight").Add(NODE + "getNodes").Send()
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, []string{BLOCK + "getChainHeight", NODE + "getNodes"}, methods)
+}
+
+func TestInterceptor_WebSocketTransport(t *testing.T) {
+	rp, _, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+
+	var urls []string
+	rp.Use(func(ctx context.Context, call *Call, next Handler) StdError {
+		urls = append(urls, call.URL)
+		assert.Nil(t, call.Header)
+		return next(ctx, call)
+	})
+	transport, err := rp.NewWebSocketTransport(1)
+	assert.Nil(t, err)
+	defer transport.Close()
+
+	_, err = rp.Transport(transport).GetNodes()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{rp.hrm.nodes[0].wsURL}, urls)
+}
+
+func TestInterceptor_AuthRawBody(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.RequireAuth(true)
+	rp.im.enable = true
+	rp.SetAccount(testKeys(t)["sm2"])
+
+	_, err := rp.GetNodes()
+	assert.Nil(t, err)
+
+	// the requests of calls made with serialized body are authenticated as well
+	single := `{"jsonrpc":"2.0","id":1,"namespace":"global","method":"node_getNodes"}`
+	data, err := rp.hrm.SyncRequestSpecificURLWithContext(context.Background(), []byte(single), "", GENERAL, nil, nil)
+	assert.Nil(t, err)
+	var resp JSONResponse
+	assert.Nil(t, json.Unmarshal(data, &resp))
+	assert.Equal(t, SuccessCode, resp.Code, resp.Message)
+
+	batch := "[" + single + "," + single + "]"
+	data, err = rp.hrm.SyncRequestSpecificURLWithContext(context.Background(), []byte(batch), "", GENERAL, nil, nil)
+	assert.Nil(t, err)
+	var resps []JSONResponse
+	assert.Nil(t, json.Unmarshal(data, &resps))
+	assert.Len(t, resps, 2)
+	for _, resp := range resps {
+		assert.Equal(t, SuccessCode, resp.Code, resp.Message)
+	}
+
+	// a body which can not be authenticated is not sent without authentication
+	_, err = rp.hrm.SyncRequestSpecificURLWithContext(context.Background(), []byte("node_getNodes"), "", GENERAL, nil, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, SystemErrorCode, err.Code())
+}
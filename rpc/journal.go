@@ -0,0 +1,292 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// TxState is the state of a journaled transaction
+type TxState string
+
+const (
+	// TxPending is a transaction which is signed but not accepted by node yet
+	TxPending TxState = "pending"
+	// TxSent is a transaction which is accepted by node, but its receipt is not got yet
+	TxSent TxState = "sent"
+	// TxConfirmed is a transaction whose receipt is got
+	TxConfirmed TxState = "confirmed"
+	// TxFailed is a transaction which is rejected by node or whose receipt is invalid
+	TxFailed TxState = "failed"
+)
+
+// journalPrefix is the key prefix of journal entries in leveldb
+var journalPrefix = []byte("tx-")
+
+// JournalEntry is a transaction recorded by Journal
+type JournalEntry struct {
+	// Hash is the hash of the transaction computed locally
+	Hash string `json:"hash"`
+	// Method is the json rpc method sending the transaction
+	Method      string       `json:"method"`
+	State       TxState      `json:"state"`
+	Transaction *Transaction `json:"transaction"`
+	// Receipt is set when the transaction is confirmed, or failed with an invalid receipt
+	Receipt *TxReceipt `json:"receipt,omitempty"`
+	// Error is the error rejecting the transaction when it is failed
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// Journal records the signed transactions and their states in leveldb, so that the transactions whose
+// receipts are not got before the process dies can be resumed by ResumeJournal after restart. It is set
+// by RPC.Journal, then the transactions sent by SendTx, DeployContract, InvokeContract, MaintainContract
+// and their variants are recorded before they are sent. Private transactions are not recorded since
+// their hashes can not be computed locally.
+type Journal struct {
+	mutex sync.Mutex
+	db    *leveldb.DB
+}
+
+// OpenJournal open the journal in the directory path, it is created if it does not exist
+func OpenJournal(path string) (*Journal, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{db: db}, nil
+}
+
+// Close closes the journal, it should not be used by RPC anymore
+func (j *Journal) Close() error {
+	return j.db.Close()
+}
+
+// Get return the entry of the transaction hash, nil if it is not journaled
+func (j *Journal) Get(hash string) (*JournalEntry, error) {
+	data, err := j.db.Get(journalKey(hash), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry JournalEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List return the entries in the states in the order they are created, all entries are returned if no state is given
+func (j *Journal) List(states ...TxState) ([]*JournalEntry, error) {
+	iter := j.db.NewIterator(util.BytesPrefix(journalPrefix), nil)
+	defer iter.Release()
+
+	var entries []*JournalEntry
+	for iter.Next() {
+		var entry JournalEntry
+		if err := json.Unmarshal(iter.Value(), &entry); err != nil {
+			return nil, err
+		}
+		if len(states) == 0 || hasTxState(states, entry.State) {
+			entries = append(entries, &entry)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, k int) bool {
+		return entries[i].Created.Before(entries[k].Created)
+	})
+	return entries, nil
+}
+
+// Prune deletes the entries in the states which are not updated since before, and return the number
+// of deleted entries. The confirmed and failed entries are deleted if no state is given.
+func (j *Journal) Prune(before time.Time, states ...TxState) (int, error) {
+	if len(states) == 0 {
+		states = []TxState{TxConfirmed, TxFailed}
+	}
+	entries, err := j.List(states...)
+	if err != nil {
+		return 0, err
+	}
+	batch := new(leveldb.Batch)
+	for _, entry := range entries {
+		if entry.Updated.Before(before) {
+			batch.Delete(journalKey(entry.Hash))
+		}
+	}
+	if err := j.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	return batch.Len(), nil
+}
+
+// Delete deletes the entry of the transaction hash
+func (j *Journal) Delete(hash string) error {
+	return j.db.Delete(journalKey(hash), nil)
+}
+
+// record records the signed transaction as pending before it is sent, the entry is kept if it exists,
+// such as the transaction is resumed
+func (j *Journal) record(method string, transaction *Transaction, hash string) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if ok, err := j.db.Has(journalKey(hash), nil); err != nil || ok {
+		return err
+	}
+	now := time.Now()
+	return j.put(&JournalEntry{
+		Hash:        hash,
+		Method:      method,
+		State:       TxPending,
+		Transaction: transaction,
+		Created:     now,
+		Updated:     now,
+	})
+}
+
+// sent marks the transaction as accepted by node
+func (j *Journal) sent(hash string) error {
+	return j.update(hash, func(entry *JournalEntry) {
+		if entry.State == TxPending {
+			entry.State = TxSent
+		}
+	})
+}
+
+// finish updates the state of the transaction by the result of sending it. The state is kept if the
+// result is unknown, such as the polling times out or the sending is canceled, so it can be resumed.
+func (j *Journal) finish(hash string, receipt *TxReceipt, err StdError) error {
+	if err != nil && (IsRetryable(err) || errors.Is(err, ErrTimeout) || errors.Is(err, ErrCanceled)) {
+		return nil
+	}
+	return j.update(hash, func(entry *JournalEntry) {
+		switch {
+		case err != nil:
+			entry.State = TxFailed
+			entry.Error = err.Error()
+		case receipt != nil && !receipt.Valid && receipt.ErrorMsg != "":
+			entry.State = TxFailed
+			entry.Receipt = receipt
+			entry.Error = receipt.ErrorMsg
+		default:
+			entry.State = TxConfirmed
+			entry.Receipt = receipt
+		}
+	})
+}
+
+// update updates the entry of hash by fn, nothing is done if it is not journaled
+func (j *Journal) update(hash string, fn func(entry *JournalEntry)) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	entry, err := j.Get(hash)
+	if err != nil || entry == nil {
+		return err
+	}
+	fn(entry)
+	entry.Updated = time.Now()
+	return j.put(entry)
+}
+
+func (j *Journal) put(entry *JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return j.db.Put(journalKey(entry.Hash), data, &opt.WriteOptions{Sync: true})
+}
+
+func journalKey(hash string) []byte {
+	return append(append([]byte{}, journalPrefix...), chPrefix(hash)...)
+}
+
+func hasTxState(states []TxState, state TxState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Journal set the journal recording the transactions sent by rpc, no transaction is recorded if it is not set
+func (rpc *RPC) Journal(journal *Journal) *RPC {
+	rpc.journal = journal
+	return rpc
+}
+
+// ResumeJournal resumes the pending and sent transactions in the journal set by Journal, which is usually
+// called after restart. The receipts of the sent transactions are waited by WaitReceipt, and the pending
+// ones or the sent ones whose receipts are not got are resent, the transactions accepted by node already
+// are not executed twice. The resumed entries are returned with their new states.
+func (rpc *RPC) ResumeJournal() ([]*JournalEntry, StdError) {
+	if rpc.journal == nil {
+		return nil, NewSystemError(errors.New("journal is not set"))
+	}
+	entries, err := rpc.journal.List(TxPending, TxSent)
+	if err != nil {
+		return nil, NewSystemError(err)
+	}
+	for i, entry := range entries {
+		if err := rpc.resumeEntry(entry); err != nil {
+			return nil, err
+		}
+		if entries[i], err = rpc.journal.Get(entry.Hash); err != nil {
+			return nil, NewSystemError(err)
+		}
+	}
+	return entries, nil
+}
+
+func (rpc *RPC) resumeEntry(entry *JournalEntry) StdError {
+	transaction := entry.Transaction
+	if entry.State == TxSent {
+		receipt, stdErr, success := rpc.WaitReceipt(entry.Hash, transaction.isPrivateTx)
+		if success {
+			return wrapJournalError(rpc.journal.finish(entry.Hash, receipt, stdErr))
+		}
+	}
+	if err := rpc.getContext().Err(); err != nil {
+		return NewRequestCanceledError(err)
+	}
+	receipt, stdErr := rpc.callByPolling(entry.Method, transaction.Serialize(), transaction.isPrivateTx, entry.Hash)
+	return wrapJournalError(rpc.journal.finish(entry.Hash, receipt, stdErr))
+}
+
+// sendByPolling sends the transaction by callByPolling, it is recorded by the journal if it is set,
+// except the simulated transactions which are not committed and never resent
+func (rpc *RPC) sendByPolling(method string, param interface{}, transaction *Transaction) (*TxReceipt, StdError) {
+	if rpc.journal == nil || transaction.simulate {
+		return rpc.callByPolling(method, param, transaction.isPrivateTx, transaction.GetHash())
+	}
+	txHash := transaction.GetHash()
+	if txHash == "" {
+		return rpc.callByPolling(method, param, transaction.isPrivateTx, txHash)
+	}
+	if err := rpc.journal.record(method, transaction, txHash); err != nil {
+		return nil, NewSystemError(err)
+	}
+	receipt, stdErr := rpc.callByPolling(method, param, transaction.isPrivateTx, txHash)
+	if err := rpc.journal.finish(txHash, receipt, stdErr); err != nil {
+		logger.Errorf("journal transaction %s failed: %v", txHash, err)
+	}
+	return receipt, stdErr
+}
+
+func wrapJournalError(err error) StdError {
+	if err != nil {
+		return NewSystemError(err)
+	}
+	return nil
+}
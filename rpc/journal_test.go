@@ -0,0 +1,132 @@
+package rpc
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+func openTestJournal(t *testing.T) (*Journal, string) {
+	dir, err := ioutil.TempDir("", "gosdk-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := OpenJournal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return journal, dir
+}
+
+func TestJournal_Send(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+	journal, dir := openTestJournal(t)
+	defer os.RemoveAll(dir)
+	defer journal.Close()
+	rp.Journal(journal)
+
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	receipt, err := rp.SendTx(tx)
+	assert.Nil(t, err)
+
+	entry, jErr := journal.Get(tx.GetHash())
+	assert.Nil(t, jErr)
+	assert.Equal(t, TxConfirmed, entry.State)
+	assert.Equal(t, TRANSACTION+"sendTransaction", entry.Method)
+	assert.Equal(t, receipt, entry.Receipt)
+	assert.Equal(t, tx.GetHash(), entry.Transaction.GetHash())
+
+	// rejected by node
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:  TRANSACTION + "sendTransaction",
+		Times:   1,
+		Code:    BalanceInsufficientCode,
+		Message: "balance insufficient",
+	})
+	failed := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 2)
+	_, err = rp.SendTx(failed)
+	assert.NotNil(t, err)
+	entry, jErr = journal.Get(failed.GetHash())
+	assert.Nil(t, jErr)
+	assert.Equal(t, TxFailed, entry.State)
+	assert.Contains(t, entry.Error, "balance insufficient")
+
+	// simulated transactions are not recorded
+	simulated := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 3).Simulate(true)
+	// whatever the result of polling, the transaction is not journaled
+	_, _ = rp.CallTxByPolling(TRANSACTION+"sendTransaction", simulated)
+	entry, jErr = journal.Get(simulated.GetHash())
+	assert.Nil(t, jErr)
+	assert.Nil(t, entry)
+
+	entries, jErr := journal.List()
+	assert.Nil(t, jErr)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, tx.GetHash(), entries[0].Hash)
+	entries, jErr = journal.List(TxFailed)
+	assert.Nil(t, jErr)
+	assert.Len(t, entries, 1)
+
+	n, jErr := journal.Prune(time.Now().Add(-time.Hour))
+	assert.Nil(t, jErr)
+	assert.Equal(t, 0, n)
+	n, jErr = journal.Prune(time.Now(), TxFailed)
+	assert.Nil(t, jErr)
+	assert.Equal(t, 1, n)
+	n, jErr = journal.Prune(time.Now())
+	assert.Nil(t, jErr)
+	assert.Equal(t, 1, n)
+	entries, jErr = journal.List()
+	assert.Nil(t, jErr)
+	assert.Empty(t, entries)
+}
+
+func TestJournal_Resume(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+	journal, dir := openTestJournal(t)
+	defer os.RemoveAll(dir)
+
+	// the process dies before the pending one is sent and before the receipt of the sent one is got
+	pending := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	pending.txVersion = rp.txVersion
+	assert.Nil(t, journal.record(TRANSACTION+"sendTransaction", pending, pending.GetHash()))
+	sent := NewTransaction(testFrom).Deploy("0x6060")
+	sent.txVersion = rp.txVersion
+	assert.Nil(t, journal.record(CONTRACT+"deployContract", sent, sent.GetHash()))
+	_, err := rp.call(CONTRACT+"deployContract", sent.Serialize())
+	assert.Nil(t, err)
+	assert.Nil(t, journal.sent(sent.GetHash()))
+	assert.Nil(t, journal.Close())
+
+	journal, err2 := OpenJournal(dir)
+	assert.Nil(t, err2)
+	defer journal.Close()
+	entries, err := rp.Journal(journal).ResumeJournal()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, TxConfirmed, entry.State, entry.Error)
+		if assert.NotNil(t, entry.Receipt) {
+			assert.Equal(t, entry.Hash, entry.Receipt.TxHash)
+		}
+	}
+	assert.Equal(t, 1, srv.Requests(TRANSACTION+"sendTransaction"))
+	// the sent one is polled instead of resent
+	assert.Equal(t, 1, srv.Requests(CONTRACT+"deployContract"))
+	assert.Len(t, srv.Transactions(), 2)
+
+	entries, err = rp.ResumeJournal()
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	_, err = rp.Journal(nil).ResumeJournal()
+	assert.NotNil(t, err)
+}
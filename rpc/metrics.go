@@ -0,0 +1,428 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buger/jsonparser"
+)
+
+// method name recorded for batch calls and calls made with serialized body
+const (
+	batchMethod   = "batch"
+	unknownMethod = "unknown"
+)
+
+var (
+	// DefaultLatencyBuckets is the upper bounds(seconds) of the latency histogram of MemoryMetrics
+	DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	// DefaultPollingBuckets is the upper bounds of the histogram of receipt queries in one polling
+	DefaultPollingBuckets = []float64{1, 2, 3, 5, 10, 20, 50, 100}
+)
+
+// MetricsSink receives the metrics of rpc.
+// Implementations must be safe for concurrent use and should not block.
+type MetricsSink interface {
+	// ObserveCall reports a request sent to node, code is the code of StdError if the request failed,
+	// the code of the json rpc response if it is not SuccessCode, or SuccessCode.
+	ObserveCall(method, node string, latency time.Duration, code int)
+	// ObserveRetry reports that a transaction of method is resent
+	ObserveRetry(method string)
+	// ObservePolling reports the number of receipt queries of one polling, code is as ObserveCall
+	ObservePolling(polls int, code int)
+	// AddInFlight adds delta to the number of requests of node waiting for response
+	AddInFlight(node string, delta int)
+	// SetNodeState reports the breaker state of node
+	SetNodeState(node string, state BreakerState)
+}
+
+// Metrics set the sink receiving metrics of rpc, no metric is recorded if it is not set.
+// It should be called before rpc is used, the current state of nodes is reported at once.
+// A sink replaced by another call or by nil receives no more metrics.
+func (rpc *RPC) Metrics(sink MetricsSink) *RPC {
+	hrm := &rpc.hrm
+	hrm.metrics = sink
+	if sink == nil {
+		return rpc
+	}
+	for _, node := range hrm.nodes {
+		sink.SetNodeState(node.url, node.State())
+	}
+	if !hrm.metricsSubscribed {
+		hrm.metricsSubscribed = true
+		hrm.health.subscribe(func(event NodeEvent) {
+			if sink := hrm.metrics; sink != nil {
+				sink.SetNodeState(event.URL, event.State)
+			}
+		})
+	}
+	return rpc
+}
+
+// metricsInterceptor records latency, result code and in-flight number of every request
+func (hrm *httpRequestManager) metricsInterceptor(ctx context.Context, call *Call, next Handler) StdError {
+	sink := hrm.metrics
+	method := unknownMethod
+	if call.Request != nil {
+		method = call.Request.Method
+	} else if call.Batch != nil {
+		method = batchMethod
+	}
+	url := call.URL
+
+	sink.AddInFlight(url, 1)
+	start := time.Now()
+	err := next(ctx, call)
+	latency := time.Since(start)
+	sink.AddInFlight(url, -1)
+
+	code := SuccessCode
+	if err != nil {
+		code = err.Code()
+	} else if c, perr := jsonparser.GetInt(call.Response, "code"); perr == nil {
+		// batch response is an array, its calls are not counted separately
+		code = int(c)
+	}
+	sink.ObserveCall(method, url, latency, code)
+	return err
+}
+
+// observeRetry reports a resend of method if metrics is enabled
+func (hrm *httpRequestManager) observeRetry(method string) {
+	if hrm.metrics != nil {
+		hrm.metrics.ObserveRetry(method)
+	}
+}
+
+// observePolling reports a receipt polling if metrics is enabled
+func (hrm *httpRequestManager) observePolling(polls int, err StdError) {
+	if hrm.metrics == nil {
+		return
+	}
+	code := SuccessCode
+	if err != nil {
+		code = err.Code()
+	}
+	hrm.metrics.ObservePolling(polls, code)
+}
+
+/*---------------------------------- memory ----------------------------------*/
+
+// Histogram is a snapshot of a histogram
+type Histogram struct {
+	// Buckets is the upper bounds of buckets in increasing order
+	Buckets []float64
+	// Counts is the cumulative count of each bucket, the count of +Inf bucket is Count
+	Counts []uint64
+	Count  uint64
+	Sum    float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		Buckets: buckets,
+		Counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *Histogram) observe(v float64) {
+	for i, upper := range h.Buckets {
+		if v <= upper {
+			h.Counts[i]++
+		}
+	}
+	h.Count++
+	h.Sum += v
+}
+
+func (h *Histogram) copy() Histogram {
+	counts := make([]uint64, len(h.Counts))
+	copy(counts, h.Counts)
+	return Histogram{Buckets: h.Buckets, Counts: counts, Count: h.Count, Sum: h.Sum}
+}
+
+// CallMetrics is the metrics of requests of a method sent to a node
+type CallMetrics struct {
+	Method string
+	Node   string
+	// Latency is in seconds
+	Latency Histogram
+	// Codes is the number of requests by result code
+	Codes map[int]uint64
+}
+
+// MetricsSnapshot is a copy of the metrics recorded by MemoryMetrics
+type MetricsSnapshot struct {
+	// Calls is sorted by method and node
+	Calls []CallMetrics
+	// Retries is the number of resends by method
+	Retries map[string]uint64
+	// Polling is the histogram of receipt queries in one polling
+	Polling Histogram
+	// PollingCodes is the number of pollings by result code
+	PollingCodes map[int]uint64
+	// InFlight is the number of requests waiting for response by node
+	InFlight   map[string]int64
+	NodeStates map[string]BreakerState
+}
+
+type callKey struct {
+	method string
+	node   string
+}
+
+type callMetrics struct {
+	latency *Histogram
+	codes   map[int]uint64
+}
+
+// MemoryMetrics is a MetricsSink keeping metrics in memory,
+// it can be read by Snapshot or exported in prometheus text format
+type MemoryMetrics struct {
+	latencyBuckets []float64
+
+	mutex        sync.Mutex
+	calls        map[callKey]*callMetrics
+	retries      map[string]uint64
+	polling      *Histogram
+	pollingCodes map[int]uint64
+	inFlight     map[string]int64
+	nodeStates   map[string]BreakerState
+}
+
+// NewMemoryMetrics return a MemoryMetrics using DefaultLatencyBuckets
+func NewMemoryMetrics() *MemoryMetrics {
+	return NewMemoryMetricsWithBuckets(DefaultLatencyBuckets)
+}
+
+// NewMemoryMetricsWithBuckets return a MemoryMetrics using the upper bounds(seconds) as latency buckets
+func NewMemoryMetricsWithBuckets(latencyBuckets []float64) *MemoryMetrics {
+	buckets := make([]float64, len(latencyBuckets))
+	copy(buckets, latencyBuckets)
+	sort.Float64s(buckets)
+	return &MemoryMetrics{
+		latencyBuckets: buckets,
+		calls:          make(map[callKey]*callMetrics),
+		retries:        make(map[string]uint64),
+		polling:        newHistogram(DefaultPollingBuckets),
+		pollingCodes:   make(map[int]uint64),
+		inFlight:       make(map[string]int64),
+		nodeStates:     make(map[string]BreakerState),
+	}
+}
+
+func (m *MemoryMetrics) ObserveCall(method, node string, latency time.Duration, code int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	key := callKey{method: method, node: node}
+	cm, ok := m.calls[key]
+	if !ok {
+		cm = &callMetrics{
+			latency: newHistogram(m.latencyBuckets),
+			codes:   make(map[int]uint64),
+		}
+		m.calls[key] = cm
+	}
+	cm.latency.observe(latency.Seconds())
+	cm.codes[code]++
+}
+
+func (m *MemoryMetrics) ObserveRetry(method string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.retries[method]++
+}
+
+func (m *MemoryMetrics) ObservePolling(polls int, code int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.polling.observe(float64(polls))
+	m.pollingCodes[code]++
+}
+
+func (m *MemoryMetrics) AddInFlight(node string, delta int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.inFlight[node] += int64(delta)
+}
+
+func (m *MemoryMetrics) SetNodeState(node string, state BreakerState) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.nodeStates[node] = state
+}
+
+// Snapshot return a copy of the recorded metrics
+func (m *MemoryMetrics) Snapshot() *MetricsSnapshot {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	snapshot := &MetricsSnapshot{
+		Calls:        make([]CallMetrics, 0, len(m.calls)),
+		Retries:      make(map[string]uint64, len(m.retries)),
+		Polling:      m.polling.copy(),
+		PollingCodes: make(map[int]uint64, len(m.pollingCodes)),
+		InFlight:     make(map[string]int64, len(m.inFlight)),
+		NodeStates:   make(map[string]BreakerState, len(m.nodeStates)),
+	}
+	for key, cm := range m.calls {
+		codes := make(map[int]uint64, len(cm.codes))
+		for code, n := range cm.codes {
+			codes[code] = n
+		}
+		snapshot.Calls = append(snapshot.Calls, CallMetrics{
+			Method:  key.method,
+			Node:    key.node,
+			Latency: cm.latency.copy(),
+			Codes:   codes,
+		})
+	}
+	sort.Slice(snapshot.Calls, func(i, j int) bool {
+		if snapshot.Calls[i].Method != snapshot.Calls[j].Method {
+			return snapshot.Calls[i].Method < snapshot.Calls[j].Method
+		}
+		return snapshot.Calls[i].Node < snapshot.Calls[j].Node
+	})
+	for method, n := range m.retries {
+		snapshot.Retries[method] = n
+	}
+	for code, n := range m.pollingCodes {
+		snapshot.PollingCodes[code] = n
+	}
+	for node, n := range m.inFlight {
+		snapshot.InFlight[node] = n
+	}
+	for node, state := range m.nodeStates {
+		snapshot.NodeStates[node] = state
+	}
+	return snapshot
+}
+
+/*---------------------------------- prometheus ----------------------------------*/
+
+// WritePrometheus writes the metrics in prometheus text format
+func (m *MemoryMetrics) WritePrometheus(w io.Writer) error {
+	s := m.Snapshot()
+	bw := bufio.NewWriter(w)
+
+	writeHeader(bw, "gosdk_request_duration_seconds", "histogram", "Latency of requests sent to nodes.")
+	for _, c := range s.Calls {
+		writeHistogram(bw, "gosdk_request_duration_seconds", c.Latency, "method", c.Method, "node", c.Node)
+	}
+
+	writeHeader(bw, "gosdk_requests_total", "counter", "Number of requests sent to nodes by result code.")
+	for _, c := range s.Calls {
+		for _, code := range sortedCodes(c.Codes) {
+			writeSample(bw, "gosdk_requests_total", float64(c.Codes[code]), "method", c.Method, "node", c.Node, "code", strconv.Itoa(code))
+		}
+	}
+
+	writeHeader(bw, "gosdk_retries_total", "counter", "Number of transaction resends.")
+	for _, method := range sortedKeys(s.Retries) {
+		writeSample(bw, "gosdk_retries_total", float64(s.Retries[method]), "method", method)
+	}
+
+	writeHeader(bw, "gosdk_receipt_polls", "histogram", "Number of receipt queries in one polling.")
+	writeHistogram(bw, "gosdk_receipt_polls", s.Polling)
+
+	writeHeader(bw, "gosdk_receipt_pollings_total", "counter", "Number of receipt pollings by result code.")
+	for _, code := range sortedCodes(s.PollingCodes) {
+		writeSample(bw, "gosdk_receipt_pollings_total", float64(s.PollingCodes[code]), "code", strconv.Itoa(code))
+	}
+
+	writeHeader(bw, "gosdk_inflight_requests", "gauge", "Number of requests waiting for response.")
+	nodes := make([]string, 0, len(s.InFlight))
+	for node := range s.InFlight {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		writeSample(bw, "gosdk_inflight_requests", float64(s.InFlight[node]), "node", node)
+	}
+
+	writeHeader(bw, "gosdk_node_up", "gauge", "Whether the node serves requests, its circuit breaker is closed.")
+	nodes = nodes[:0]
+	for node := range s.NodeStates {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		up := 0.0
+		if s.NodeStates[node] == BreakerClosed {
+			up = 1
+		}
+		writeSample(bw, "gosdk_node_up", up, "node", node)
+	}
+
+	return bw.Flush()
+}
+
+// PrometheusHandler return a http handler exporting the metrics of m in prometheus text format
+func PrometheusHandler(m *MemoryMetrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := m.WritePrometheus(w); err != nil {
+			logger.Errorf("export metrics error: %v", err)
+		}
+	})
+}
+
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func writeHistogram(w io.Writer, name string, h Histogram, labels ...string) {
+	for i, upper := range h.Buckets {
+		writeSample(w, name+"_bucket", float64(h.Counts[i]), append(labels[:len(labels):len(labels)], "le", formatFloat(upper))...)
+	}
+	writeSample(w, name+"_bucket", float64(h.Count), append(labels[:len(labels):len(labels)], "le", "+Inf")...)
+	writeSample(w, name+"_sum", h.Sum, labels...)
+	writeSample(w, name+"_count", float64(h.Count), labels...)
+}
+
+// writeSample writes a sample, labels are name value pairs
+func writeSample(w io.Writer, name string, value float64, labels ...string) {
+	if len(labels) == 0 {
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+		return
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+"="+strconv.Quote(labels[i+1]))
+	}
+	fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatFloat(value))
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedCodes(m map[int]uint64) []int {
+	codes := make([]int, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
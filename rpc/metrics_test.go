@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryMetrics(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	metrics := NewMemoryMetrics()
+	rp.Metrics(metrics)
+	srv.SetReceiptDelay(2)
+
+	_, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.Nil(t, err)
+	_, err = rp.GetBlockByNumber(100, true)
+	assert.Equal(t, DataNotExistCode, err.Code())
+
+	url := rp.hrm.nodes[0].url
+	s := metrics.Snapshot()
+	byMethod := make(map[string]CallMetrics)
+	for _, c := range s.Calls {
+		assert.Equal(t, url, c.Node)
+		byMethod[c.Method] = c
+	}
+	assert.Equal(t, map[int]uint64{SuccessCode: 1}, byMethod[TRANSACTION+"sendTransaction"].Codes)
+	assert.Equal(t, map[int]uint64{DataNotExistCode: 2, SuccessCode: 1}, byMethod[TRANSACTION+"getTransactionReceipt"].Codes)
+	assert.Equal(t, map[int]uint64{DataNotExistCode: 1}, byMethod[BLOCK+"getBlockByNumber"].Codes)
+	assert.Equal(t, uint64(3), byMethod[TRANSACTION+"getTransactionReceipt"].Latency.Count)
+
+	assert.Equal(t, uint64(1), s.Polling.Count)
+	assert.Equal(t, float64(3), s.Polling.Sum)
+	assert.Equal(t, map[int]uint64{SuccessCode: 1}, s.PollingCodes)
+	assert.Equal(t, int64(0), s.InFlight[url])
+	assert.Equal(t, BreakerClosed, s.NodeStates[url])
+	assert.Empty(t, s.Retries)
+}
+
+func TestMemoryMetrics_RetryAndNodeState(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	// the replaced sink receives no node events
+	replaced := NewMemoryMetrics()
+	rp.Metrics(replaced)
+	metrics := NewMemoryMetrics()
+	rp.Metrics(metrics)
+	assert.Len(t, rp.hrm.health.handlers, 1)
+
+	// receipt is never found, so the transaction is resent and its receipt is polled again
+	srv.SetReceiptDelay(100)
+	_, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.NotNil(t, err)
+	s := metrics.Snapshot()
+	assert.Equal(t, map[string]uint64{TRANSACTION + "sendTransaction": 1}, s.Retries)
+	assert.Equal(t, uint64(2), s.PollingCodes[GetResponseErrorCode])
+
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{Drop: true})
+	_, err = rp.GetNodes()
+	assert.Equal(t, GetResponseErrorCode, err.Code())
+	url := rp.hrm.nodes[0].url
+	s = metrics.Snapshot()
+	assert.Equal(t, BreakerOpen, s.NodeStates[url])
+	assert.Equal(t, BreakerClosed, replaced.Snapshot().NodeStates[url])
+
+	srv.ClearFaults()
+	assert.Eventually(t, func() bool {
+		return metrics.Snapshot().NodeStates[url] == BreakerClosed
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Empty(t, replaced.Snapshot().Calls)
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	metrics := NewMemoryMetricsWithBuckets([]float64{0.1, 1})
+	metrics.ObserveCall("block_latestBlock", "http://127.0.0.1:8081", 50*time.Millisecond, SuccessCode)
+	metrics.ObserveCall("block_latestBlock", "http://127.0.0.1:8081", 500*time.Millisecond, DataNotExistCode)
+	metrics.ObserveRetry("tx_sendTransaction")
+	metrics.ObservePolling(3, SuccessCode)
+	metrics.AddInFlight("http://127.0.0.1:8081", 1)
+	metrics.SetNodeState("http://127.0.0.1:8081", BreakerOpen)
+
+	w := httptest.NewRecorder()
+	PrometheusHandler(metrics).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := ioutil.ReadAll(w.Body)
+	text := string(body)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+
+	for _, line := range []string{
+		"# TYPE gosdk_request_duration_seconds histogram",
+		`gosdk_request_duration_seconds_bucket{method="block_latestBlock",node="http://127.0.0.1:8081",le="0.1"} 1`,
+		`gosdk_request_duration_seconds_bucket{method="block_latestBlock",node="http://127.0.0.1:8081",le="1"} 2`,
+		`gosdk_request_duration_seconds_bucket{method="block_latestBlock",node="http://127.0.0.1:8081",le="+Inf"} 2`,
+		`gosdk_request_duration_seconds_count{method="block_latestBlock",node="http://127.0.0.1:8081"} 2`,
+		`gosdk_requests_total{method="block_latestBlock",node="http://127.0.0.1:8081",code="-32001"} 1`,
+		`gosdk_requests_total{method="block_latestBlock",node="http://127.0.0.1:8081",code="0"} 1`,
+		`gosdk_retries_total{method="tx_sendTransaction"} 1`,
+		`gosdk_receipt_polls_bucket{le="3"} 1`,
+		"gosdk_receipt_polls_sum 3",
+		`gosdk_receipt_pollings_total{code="0"} 1`,
+		`gosdk_inflight_requests{node="http://127.0.0.1:8081"} 1`,
+		`gosdk_node_up{node="http://127.0.0.1:8081"} 0`,
+	} {
+		assert.Contains(t, text, line+"\n")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hyperchain/gosdk/account"
 	"github.com/hyperchain/gosdk/common"
 )
 
@@ -136,9 +137,9 @@ func (rm *RegisterMeta) SetDelay(delay bool) *RegisterMeta {
 //	return rm
 //}
 
-// Sign sign RegisterMeta
-func (rm *RegisterMeta) Sign(key interface{}) {
-	sig, err := sign(key, concatNeedHash(rm), false, false)
+// Sign sign RegisterMeta, key is an account.Key or account.Signer
+func (rm *RegisterMeta) Sign(key account.Signer) {
+	sig, err := sign(key, concatNeedHash(rm), false)
 	if err != nil {
 		return
 	}
@@ -262,10 +263,10 @@ func NewUnRegisterMeta(from, queue, exchange string) *UnRegisterMeta {
 	}
 }
 
-// Sign sign UnRegisterMeta
-func (urm *UnRegisterMeta) Sign(key interface{}) {
+// Sign sign UnRegisterMeta, key is an account.Key or account.Signer
+func (urm *UnRegisterMeta) Sign(key account.Signer) {
 	needHash := urm.QueueName + ":" + urm.ExchangeName
-	sig, err := sign(key, needHash, false, false)
+	sig, err := sign(key, needHash, false)
 	if err != nil {
 		logger.Error("ecdsa signature error")
 		return
@@ -149,7 +149,6 @@ func TestRPCMqClient(t *testing.T) {
 	rm.AddAddress(*new(common.Address))
 
 	//rm.Sign(new(ecdsa.Key))
-	rm.Sign(1)
 	rm.Sign(guomiKey)
 	rm.Serialize()
 	rm.SerializeToString()
@@ -34,7 +34,11 @@ func NewPrivateTransaction(from string, participants []string) *Transaction {
 
 // GenSinPub is used to signature needString
 func GenSinPub(key account.Key, needHashString string) string {
-	sig, err := sign(key, needHashString, false, false)
+	return genSinPub(key, needHashString)
+}
+
+func genSinPub(key account.Signer, needHashString string) string {
+	sig, err := sign(key, needHashString, false)
 	if err != nil {
 		logger.Error("ecdsa signature error")
 		return ""
@@ -44,6 +48,10 @@ func GenSinPub(key account.Key, needHashString string) string {
 
 // PreSign is used to constructor extra field in private transaction
 func (t *Transaction) PreSign(key account.Key) {
+	t.preSign(key)
+}
+
+func (t *Transaction) preSign(key account.Signer) {
 	sha3ToHex := func(value []byte) string {
 		h := sha3.NewKeccak256()
 		_, _ = h.Write(value)
@@ -90,7 +98,7 @@ func (t *Transaction) PreSign(key account.Key) {
 	t.extra = pubTxExtra.Stringify()
 	t.payload = ""
 
-	pubSig := GenSinPub(key, needHashString(t))
+	pubSig := genSinPub(key, needHashString(t))
 
 	// --- create sig_pri ---
 	extraBytesRaw := []byte(t.extra)
@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MarshalJSON serialize all fields of the transaction to json, including the tx version, extraIds, kvExtra
+// and the participants of private transaction, so it can be restored by UnmarshalJSON or ParseTransaction
+// on another host, such as an air-gapped one signing the transaction
+func (t *Transaction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.toRaw())
+}
+
+// UnmarshalJSON restore the transaction serialized by MarshalJSON
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	var raw RawTransaction
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return t.fromRaw(&raw)
+}
+
+// MarshalProto serialize all fields of the transaction to protobuf bytes of RawTransaction
+func (t *Transaction) MarshalProto() ([]byte, error) {
+	return t.toRaw().Marshal()
+}
+
+// UnmarshalProto restore the transaction serialized by MarshalProto
+func (t *Transaction) UnmarshalProto(data []byte) error {
+	var raw RawTransaction
+	if err := raw.Unmarshal(data); err != nil {
+		return err
+	}
+	return t.fromRaw(&raw)
+}
+
+// ParseTransaction restore a transaction serialized by MarshalJSON or MarshalProto,
+// the format is detected by the first non-space byte, which is '{' for json only
+func ParseTransaction(data []byte) (*Transaction, error) {
+	t := new(Transaction)
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = t.UnmarshalJSON(trimmed)
+	} else {
+		err = t.UnmarshalProto(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Transaction) toRaw() *RawTransaction {
+	raw := &RawTransaction{
+		From:          t.from,
+		To:            t.to,
+		Value:         t.value,
+		Payload:       t.payload,
+		Timestamp:     t.timestamp,
+		Nonce:         t.nonce,
+		Signature:     t.signature,
+		Opcode:        t.opcode,
+		VmType:        t.vmType,
+		Simulate:      t.simulate,
+		IsValue:       t.isValue,
+		IsDeploy:      t.isDeploy,
+		IsMaintain:    t.isMaintain,
+		IsInvoke:      t.isInvoke,
+		IsByName:      t.isByName,
+		Extra:         t.extra,
+		HasExtra:      t.hasExtra,
+		ExtraIdInt64:  t.extraIdInt64,
+		ExtraIdString: t.extraIdString,
+		CName:         t.cName,
+		TxVersion:     t.txVersion,
+		Participants:  t.participants,
+		IsPrivateTx:   t.isPrivateTx,
+	}
+	if t.kvExtra != nil {
+		raw.KvExtra = t.kvExtra.Stringify()
+	}
+	return raw
+}
+
+func (t *Transaction) fromRaw(raw *RawTransaction) error {
+	var kvExtra *KVExtra
+	if raw.KvExtra != "" {
+		// keep numbers as they are, so kvExtra is stringified the same way again
+		decoder := json.NewDecoder(bytes.NewReader([]byte(raw.KvExtra)))
+		decoder.UseNumber()
+		data := make(map[string]interface{})
+		if err := decoder.Decode(&data); err != nil {
+			return fmt.Errorf("invalid kvExtra: %v", err)
+		}
+		kvExtra = &KVExtra{data: data}
+	}
+	*t = Transaction{
+		from:          raw.From,
+		to:            raw.To,
+		value:         raw.Value,
+		payload:       raw.Payload,
+		timestamp:     raw.Timestamp,
+		nonce:         raw.Nonce,
+		signature:     raw.Signature,
+		opcode:        raw.Opcode,
+		vmType:        raw.VmType,
+		simulate:      raw.Simulate,
+		isValue:       raw.IsValue,
+		isDeploy:      raw.IsDeploy,
+		isMaintain:    raw.IsMaintain,
+		isInvoke:      raw.IsInvoke,
+		isByName:      raw.IsByName,
+		extra:         raw.Extra,
+		kvExtra:       kvExtra,
+		hasExtra:      raw.HasExtra,
+		extraIdInt64:  raw.ExtraIdInt64,
+		extraIdString: raw.ExtraIdString,
+		cName:         raw.CName,
+		txVersion:     raw.TxVersion,
+		participants:  raw.Participants,
+		isPrivateTx:   raw.IsPrivateTx,
+	}
+	return nil
+}
+
+// SendRawTransaction send a transaction serialized by MarshalJSON or MarshalProto and poll its receipt,
+// which is usually built by another host and signed offline. It is sent as SendTx, DeployContract,
+// InvokeContract or MaintainContract does according to how it was built. The tx version of the transaction
+// is signed, so it must be the one of the chain.
+func (rpc *RPC) SendRawTransaction(data []byte) (*TxReceipt, StdError) {
+	transaction, err := ParseTransaction(data)
+	if err != nil {
+		return nil, NewSystemError(err)
+	}
+	if transaction.txVersion != "" && transaction.txVersion != rpc.txVersion {
+		return nil, NewSystemError(fmt.Errorf("tx version %s of the transaction is not %s of the chain", transaction.txVersion, rpc.txVersion))
+	}
+	if transaction.signature == "" {
+		return nil, NewSystemError(errors.New("the transaction is not signed"))
+	}
+	return rpc.sendTransaction(transaction)
+}
@@ -0,0 +1,1450 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: raw_transaction.proto
+
+package rpc
+
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// RawTransaction is the serialized form of Transaction, all fields of Transaction are kept
+type RawTransaction struct {
+	From          string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Value         int64    `protobuf:"varint,3,opt,name=value,proto3" json:"value,omitempty"`
+	Payload       string   `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	Timestamp     int64    `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Nonce         int64    `protobuf:"varint,6,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Signature     string   `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	Opcode        int64    `protobuf:"varint,8,opt,name=opcode,proto3" json:"opcode,omitempty"`
+	VmType        string   `protobuf:"bytes,9,opt,name=vmType,proto3" json:"vmType,omitempty"`
+	Simulate      bool     `protobuf:"varint,10,opt,name=simulate,proto3" json:"simulate,omitempty"`
+	IsValue       bool     `protobuf:"varint,11,opt,name=isValue,proto3" json:"isValue,omitempty"`
+	IsDeploy      bool     `protobuf:"varint,12,opt,name=isDeploy,proto3" json:"isDeploy,omitempty"`
+	IsMaintain    bool     `protobuf:"varint,13,opt,name=isMaintain,proto3" json:"isMaintain,omitempty"`
+	IsInvoke      bool     `protobuf:"varint,14,opt,name=isInvoke,proto3" json:"isInvoke,omitempty"`
+	IsByName      bool     `protobuf:"varint,15,opt,name=isByName,proto3" json:"isByName,omitempty"`
+	Extra         string   `protobuf:"bytes,16,opt,name=extra,proto3" json:"extra,omitempty"`
+	HasExtra      bool     `protobuf:"varint,17,opt,name=hasExtra,proto3" json:"hasExtra,omitempty"`
+	KvExtra       string   `protobuf:"bytes,18,opt,name=kvExtra,proto3" json:"kvExtra,omitempty"`
+	ExtraIdInt64  []int64  `protobuf:"varint,19,rep,packed,name=extraIdInt64,proto3" json:"extraIdInt64,omitempty"`
+	ExtraIdString []string `protobuf:"bytes,20,rep,name=extraIdString,proto3" json:"extraIdString,omitempty"`
+	CName         string   `protobuf:"bytes,21,opt,name=cName,proto3" json:"cName,omitempty"`
+	TxVersion     string   `protobuf:"bytes,22,opt,name=txVersion,proto3" json:"txVersion,omitempty"`
+	Participants  []string `protobuf:"bytes,23,rep,name=participants,proto3" json:"participants,omitempty"`
+	IsPrivateTx   bool     `protobuf:"varint,24,opt,name=isPrivateTx,proto3" json:"isPrivateTx,omitempty"`
+}
+
+func (m *RawTransaction) Reset()         { *m = RawTransaction{} }
+func (m *RawTransaction) String() string { return proto.CompactTextString(m) }
+func (*RawTransaction) ProtoMessage()    {}
+func (*RawTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1ecfc329fdeda7ed, []int{0}
+}
+func (m *RawTransaction) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RawTransaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RawTransaction.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RawTransaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RawTransaction.Merge(m, src)
+}
+func (m *RawTransaction) XXX_Size() int {
+	return m.Size()
+}
+func (m *RawTransaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_RawTransaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RawTransaction proto.InternalMessageInfo
+
+func (m *RawTransaction) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *RawTransaction) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *RawTransaction) GetNonce() int64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *RawTransaction) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetOpcode() int64 {
+	if m != nil {
+		return m.Opcode
+	}
+	return 0
+}
+
+func (m *RawTransaction) GetVmType() string {
+	if m != nil {
+		return m.VmType
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetSimulate() bool {
+	if m != nil {
+		return m.Simulate
+	}
+	return false
+}
+
+func (m *RawTransaction) GetIsValue() bool {
+	if m != nil {
+		return m.IsValue
+	}
+	return false
+}
+
+func (m *RawTransaction) GetIsDeploy() bool {
+	if m != nil {
+		return m.IsDeploy
+	}
+	return false
+}
+
+func (m *RawTransaction) GetIsMaintain() bool {
+	if m != nil {
+		return m.IsMaintain
+	}
+	return false
+}
+
+func (m *RawTransaction) GetIsInvoke() bool {
+	if m != nil {
+		return m.IsInvoke
+	}
+	return false
+}
+
+func (m *RawTransaction) GetIsByName() bool {
+	if m != nil {
+		return m.IsByName
+	}
+	return false
+}
+
+func (m *RawTransaction) GetExtra() string {
+	if m != nil {
+		return m.Extra
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetHasExtra() bool {
+	if m != nil {
+		return m.HasExtra
+	}
+	return false
+}
+
+func (m *RawTransaction) GetKvExtra() string {
+	if m != nil {
+		return m.KvExtra
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetExtraIdInt64() []int64 {
+	if m != nil {
+		return m.ExtraIdInt64
+	}
+	return nil
+}
+
+func (m *RawTransaction) GetExtraIdString() []string {
+	if m != nil {
+		return m.ExtraIdString
+	}
+	return nil
+}
+
+func (m *RawTransaction) GetCName() string {
+	if m != nil {
+		return m.CName
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetTxVersion() string {
+	if m != nil {
+		return m.TxVersion
+	}
+	return ""
+}
+
+func (m *RawTransaction) GetParticipants() []string {
+	if m != nil {
+		return m.Participants
+	}
+	return nil
+}
+
+func (m *RawTransaction) GetIsPrivateTx() bool {
+	if m != nil {
+		return m.IsPrivateTx
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*RawTransaction)(nil), "rpc.RawTransaction")
+}
+
+func init() { proto.RegisterFile("raw_transaction.proto", fileDescriptor_1ecfc329fdeda7ed) }
+
+var fileDescriptor_1ecfc329fdeda7ed = []byte{
+	// 433 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x92, 0x41, 0x6e, 0xd3, 0x40,
+	0x14, 0x86, 0xe3, 0xb8, 0x49, 0x93, 0x69, 0x1b, 0x60, 0x68, 0xcb, 0x13, 0x42, 0x96, 0x55, 0xb1,
+	0xf0, 0x8a, 0x0d, 0x88, 0x03, 0x54, 0xb0, 0xc8, 0x02, 0x84, 0x4c, 0xd4, 0x2d, 0x7a, 0x38, 0x43,
+	0x19, 0x35, 0x9e, 0x19, 0xcd, 0x4c, 0xdc, 0xe4, 0x16, 0x1c, 0x81, 0xe3, 0xb0, 0xec, 0x92, 0x25,
+	0x4a, 0x2e, 0x82, 0xe6, 0x8d, 0xdd, 0x24, 0x3b, 0x7f, 0xff, 0xef, 0xff, 0xf9, 0x7f, 0x33, 0x66,
+	0x17, 0x16, 0xef, 0xbf, 0x79, 0x8b, 0xca, 0x61, 0xe5, 0xa5, 0x56, 0x6f, 0x8c, 0xd5, 0x5e, 0xf3,
+	0xd4, 0x9a, 0xea, 0xea, 0xf7, 0x80, 0x4d, 0x4a, 0xbc, 0x9f, 0xed, 0x5c, 0xce, 0xd9, 0xd1, 0x0f,
+	0xab, 0x6b, 0x48, 0xf2, 0xa4, 0x18, 0x97, 0xf4, 0xcc, 0x27, 0xac, 0xef, 0x35, 0xf4, 0x49, 0xe9,
+	0x7b, 0xcd, 0xcf, 0xd9, 0xa0, 0xc1, 0xc5, 0x52, 0x40, 0x9a, 0x27, 0x45, 0x5a, 0x46, 0xe0, 0xc0,
+	0x8e, 0x0d, 0xae, 0x17, 0x1a, 0xe7, 0x70, 0x44, 0xaf, 0x76, 0xc8, 0x5f, 0xb1, 0xb1, 0x97, 0xb5,
+	0x70, 0x1e, 0x6b, 0x03, 0x03, 0xca, 0xec, 0x84, 0x30, 0x4d, 0x69, 0x55, 0x09, 0x18, 0xc6, 0x69,
+	0x04, 0x21, 0xe3, 0xe4, 0xad, 0x42, 0xbf, 0xb4, 0x02, 0x8e, 0x69, 0xde, 0x4e, 0xe0, 0x97, 0x6c,
+	0xa8, 0x4d, 0xa5, 0xe7, 0x02, 0x46, 0x14, 0x6a, 0x29, 0xe8, 0x4d, 0x3d, 0x5b, 0x1b, 0x01, 0x63,
+	0x8a, 0xb4, 0xc4, 0x5f, 0xb2, 0x91, 0x93, 0xf5, 0x72, 0x81, 0x5e, 0x00, 0xcb, 0x93, 0x62, 0x54,
+	0x3e, 0x72, 0xe8, 0x2d, 0xdd, 0x0d, 0xed, 0x73, 0x42, 0x56, 0x87, 0x21, 0x25, 0xdd, 0x07, 0x61,
+	0x16, 0x7a, 0x0d, 0xa7, 0x31, 0xd5, 0x31, 0xcf, 0x18, 0x93, 0xee, 0x13, 0x4a, 0xe5, 0x51, 0x2a,
+	0x38, 0x23, 0x77, 0x4f, 0x89, 0xd9, 0xa9, 0x6a, 0xf4, 0x9d, 0x80, 0x49, 0x97, 0x8d, 0x1c, 0xbd,
+	0xeb, 0xf5, 0x67, 0xac, 0x05, 0x3c, 0xe9, 0xbc, 0xc8, 0xe1, 0x34, 0xc4, 0xca, 0x5b, 0x84, 0xa7,
+	0xb4, 0x40, 0x84, 0x90, 0xf8, 0x89, 0xee, 0x23, 0x19, 0xcf, 0x62, 0xa2, 0xe3, 0xd0, 0xff, 0xae,
+	0x89, 0x16, 0x8f, 0xe7, 0xde, 0x22, 0xbf, 0x62, 0xa7, 0x14, 0x9f, 0xce, 0xa7, 0xca, 0xbf, 0x7f,
+	0x07, 0xcf, 0xf3, 0xb4, 0x48, 0xcb, 0x03, 0x8d, 0xbf, 0x66, 0x67, 0x2d, 0x7f, 0xf5, 0x56, 0xaa,
+	0x5b, 0x38, 0xcf, 0xd3, 0x62, 0x5c, 0x1e, 0x8a, 0xa1, 0x55, 0x45, 0x75, 0x2f, 0x62, 0x2b, 0x02,
+	0xba, 0xd7, 0xd5, 0x8d, 0xb0, 0x4e, 0x6a, 0x05, 0x97, 0xf1, 0x8e, 0x1e, 0x85, 0xf0, 0x75, 0x83,
+	0xd6, 0xcb, 0x4a, 0x1a, 0x54, 0xde, 0xc1, 0x0b, 0x1a, 0x7c, 0xa0, 0xf1, 0x9c, 0x9d, 0x48, 0xf7,
+	0xc5, 0xca, 0x06, 0xbd, 0x98, 0xad, 0x00, 0x68, 0xb5, 0x7d, 0xe9, 0x1a, 0xfe, 0x6c, 0xb2, 0xe4,
+	0x61, 0x93, 0x25, 0xff, 0x36, 0x59, 0xf2, 0x6b, 0x9b, 0xf5, 0x1e, 0xb6, 0x59, 0xef, 0xef, 0x36,
+	0xeb, 0x7d, 0x1f, 0xd2, 0x8f, 0xfc, 0xf6, 0xff, 0x00, 0x40, 0xf1, 0x02, 0x37, 0xe1, 0x02, 0x00,
+	0x00,
+}
+
+func (m *RawTransaction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RawTransaction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RawTransaction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.IsPrivateTx {
+		i--
+		if m.IsPrivateTx {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc0
+	}
+	if len(m.Participants) > 0 {
+		for iNdEx := len(m.Participants) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Participants[iNdEx])
+			copy(dAtA[i:], m.Participants[iNdEx])
+			i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.Participants[iNdEx])))
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xba
+		}
+	}
+	if len(m.TxVersion) > 0 {
+		i -= len(m.TxVersion)
+		copy(dAtA[i:], m.TxVersion)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.TxVersion)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb2
+	}
+	if len(m.CName) > 0 {
+		i -= len(m.CName)
+		copy(dAtA[i:], m.CName)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.CName)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xaa
+	}
+	if len(m.ExtraIdString) > 0 {
+		for iNdEx := len(m.ExtraIdString) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ExtraIdString[iNdEx])
+			copy(dAtA[i:], m.ExtraIdString[iNdEx])
+			i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.ExtraIdString[iNdEx])))
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xa2
+		}
+	}
+	if len(m.ExtraIdInt64) > 0 {
+		dAtA2 := make([]byte, len(m.ExtraIdInt64)*10)
+		var j1 int
+		for _, num1 := range m.ExtraIdInt64 {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA2[j1] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j1++
+			}
+			dAtA2[j1] = uint8(num)
+			j1++
+		}
+		i -= j1
+		copy(dAtA[i:], dAtA2[:j1])
+		i = encodeVarintRawTransaction(dAtA, i, uint64(j1))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x9a
+	}
+	if len(m.KvExtra) > 0 {
+		i -= len(m.KvExtra)
+		copy(dAtA[i:], m.KvExtra)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.KvExtra)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
+	}
+	if m.HasExtra {
+		i--
+		if m.HasExtra {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
+	}
+	if len(m.Extra) > 0 {
+		i -= len(m.Extra)
+		copy(dAtA[i:], m.Extra)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.Extra)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	if m.IsByName {
+		i--
+		if m.IsByName {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x78
+	}
+	if m.IsInvoke {
+		i--
+		if m.IsInvoke {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x70
+	}
+	if m.IsMaintain {
+		i--
+		if m.IsMaintain {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x68
+	}
+	if m.IsDeploy {
+		i--
+		if m.IsDeploy {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x60
+	}
+	if m.IsValue {
+		i--
+		if m.IsValue {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.Simulate {
+		i--
+		if m.Simulate {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if len(m.VmType) > 0 {
+		i -= len(m.VmType)
+		copy(dAtA[i:], m.VmType)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.VmType)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.Opcode != 0 {
+		i = encodeVarintRawTransaction(dAtA, i, uint64(m.Opcode))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Nonce != 0 {
+		i = encodeVarintRawTransaction(dAtA, i, uint64(m.Nonce))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintRawTransaction(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Value != 0 {
+		i = encodeVarintRawTransaction(dAtA, i, uint64(m.Value))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.To) > 0 {
+		i -= len(m.To)
+		copy(dAtA[i:], m.To)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.To)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.From) > 0 {
+		i -= len(m.From)
+		copy(dAtA[i:], m.From)
+		i = encodeVarintRawTransaction(dAtA, i, uint64(len(m.From)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintRawTransaction(dAtA []byte, offset int, v uint64) int {
+	offset -= sovRawTransaction(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *RawTransaction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.From)
+	if l > 0 {
+		n += 1 + l + sovRawTransaction(uint64(l))
+	}
+	l = len(m.To)
+	if l > 0 {
+		n += 1 + l + sovRawTransaction(uint64(l))
+	}
+	if m.Value != 0 {
+		n += 1 + sovRawTransaction(uint64(m.Value))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovRawTransaction(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovRawTransaction(uint64(m.Timestamp))
+	}
+	if m.Nonce != 0 {
+		n += 1 + sovRawTransaction(uint64(m.Nonce))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovRawTransaction(uint64(l))
+	}
+	if m.Opcode != 0 {
+		n += 1 + sovRawTransaction(uint64(m.Opcode))
+	}
+	l = len(m.VmType)
+	if l > 0 {
+		n += 1 + l + sovRawTransaction(uint64(l))
+	}
+	if m.Simulate {
+		n += 2
+	}
+	if m.IsValue {
+		n += 2
+	}
+	if m.IsDeploy {
+		n += 2
+	}
+	if m.IsMaintain {
+		n += 2
+	}
+	if m.IsInvoke {
+		n += 2
+	}
+	if m.IsByName {
+		n += 2
+	}
+	l = len(m.Extra)
+	if l > 0 {
+		n += 2 + l + sovRawTransaction(uint64(l))
+	}
+	if m.HasExtra {
+		n += 3
+	}
+	l = len(m.KvExtra)
+	if l > 0 {
+		n += 2 + l + sovRawTransaction(uint64(l))
+	}
+	if len(m.ExtraIdInt64) > 0 {
+		l = 0
+		for _, e := range m.ExtraIdInt64 {
+			l += sovRawTransaction(uint64(e))
+		}
+		n += 2 + sovRawTransaction(uint64(l)) + l
+	}
+	if len(m.ExtraIdString) > 0 {
+		for _, s := range m.ExtraIdString {
+			l = len(s)
+			n += 2 + l + sovRawTransaction(uint64(l))
+		}
+	}
+	l = len(m.CName)
+	if l > 0 {
+		n += 2 + l + sovRawTransaction(uint64(l))
+	}
+	l = len(m.TxVersion)
+	if l > 0 {
+		n += 2 + l + sovRawTransaction(uint64(l))
+	}
+	if len(m.Participants) > 0 {
+		for _, s := range m.Participants {
+			l = len(s)
+			n += 2 + l + sovRawTransaction(uint64(l))
+		}
+	}
+	if m.IsPrivateTx {
+		n += 3
+	}
+	return n
+}
+
+func sovRawTransaction(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozRawTransaction(x uint64) (n int) {
+	return sovRawTransaction(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *RawTransaction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRawTransaction
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RawTransaction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RawTransaction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.From = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.To = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			m.Value = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Value |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			m.Nonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Nonce |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Opcode", wireType)
+			}
+			m.Opcode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Opcode |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VmType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.VmType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Simulate", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Simulate = bool(v != 0)
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsValue", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsValue = bool(v != 0)
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsDeploy", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsDeploy = bool(v != 0)
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsMaintain", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsMaintain = bool(v != 0)
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsInvoke", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsInvoke = bool(v != 0)
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsByName", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsByName = bool(v != 0)
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Extra", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Extra = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HasExtra", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HasExtra = bool(v != 0)
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KvExtra", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KvExtra = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 19:
+			if wireType == 0 {
+				var v int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRawTransaction
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.ExtraIdInt64 = append(m.ExtraIdInt64, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRawTransaction
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRawTransaction
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRawTransaction
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.ExtraIdInt64) == 0 {
+					m.ExtraIdInt64 = make([]int64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRawTransaction
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.ExtraIdInt64 = append(m.ExtraIdInt64, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExtraIdInt64", wireType)
+			}
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExtraIdString", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExtraIdString = append(m.ExtraIdString, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Participants", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Participants = append(m.Participants, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 24:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsPrivateTx", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsPrivateTx = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRawTransaction(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRawTransaction
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipRawTransaction(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowRawTransaction
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowRawTransaction
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthRawTransaction
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupRawTransaction
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthRawTransaction
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthRawTransaction        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowRawTransaction          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupRawTransaction = fmt.Errorf("proto: unexpected end of group")
+)
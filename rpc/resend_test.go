@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+// hashLikeClient lets the test server compute transaction hash the same way as client
+func hashLikeClient(rp *RPC) rpctest.TxHasher {
+	return func(tx *rpctest.Tx) string {
+		return (&Transaction{
+			from:      tx.From,
+			to:        tx.To,
+			value:     tx.Value,
+			payload:   tx.Payload,
+			timestamp: tx.Timestamp,
+			nonce:     tx.Nonce,
+			signature: tx.Signature,
+			opcode:    int64(tx.Opcode),
+			vmType:    tx.VMType,
+			extra:     tx.Extra,
+			txVersion: rp.txVersion,
+		}).GetHash()
+	}
+}
+
+func TestTransaction_GetHash(t *testing.T) {
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	tx.txVersion = "1.0"
+	assert.Equal(t, tx.GetTransactionHash(DefaultTxGasLimit), tx.GetHash())
+	tx.txVersion = "2.5"
+	assert.Equal(t, tx.GetTransactionHash(DefaultTxGasLimitV2), tx.GetHash())
+	assert.NotEqual(t, "", tx.GetHash())
+
+	tx.isPrivateTx = true
+	assert.Equal(t, "", tx.GetHash())
+}
+
+func TestCallByPolling_ResponseLost(t *testing.T) {
+	// the node dropping the response is unavailable for a while, so the request is sent to another one
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+
+	// the transaction is accepted but its response is lost
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:       TRANSACTION + "sendTransaction",
+		Times:        1,
+		DropResponse: true,
+	})
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	receipt, err := rp.SendTx(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, tx.GetHash(), receipt.TxHash)
+	// the receipt of the hash is found before resend
+	assert.Equal(t, 1, srv.Requests(TRANSACTION+"sendTransaction"))
+	assert.Equal(t, 1, srv.Requests(TRANSACTION+"getTransactionReceipt"))
+}
+
+func TestCallByPolling_DuplicateTx(t *testing.T) {
+	// the node dropping the response is unavailable for a while, so the request is sent to another one
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+
+	// the response is lost and the receipt is not ready before resend
+	srv.SetReceiptDelay(1)
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:       TRANSACTION + "sendTransaction",
+		Times:        1,
+		DropResponse: true,
+	})
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	receipt, err := rp.SendTx(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, tx.GetHash(), receipt.TxHash)
+	// the resent transaction is rejected as duplicate, then its receipt is polled
+	assert.Equal(t, 2, srv.Requests(TRANSACTION+"sendTransaction"))
+
+	// a duplicate transaction without local hash is still an error
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:  TRANSACTION + "sendTransaction",
+		Times:   1,
+		Code:    DuplicateTransactionsCode,
+		Message: "duplicate transaction",
+	})
+	_, err = rp.CallByPolling(TRANSACTION+"sendTransaction", tx.Serialize(), false)
+	assert.Equal(t, DuplicateTransactionsCode, err.Code())
+
+	// the transaction passed to CallByPolling is sent with its hash
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:  TRANSACTION + "sendTransaction",
+		Times:   1,
+		Code:    DuplicateTransactionsCode,
+		Message: "duplicate transaction",
+	})
+	receipt, err = rp.CallByPolling(TRANSACTION+"sendTransaction", tx, false)
+	assert.Nil(t, err)
+	assert.Equal(t, tx.GetHash(), receipt.TxHash)
+}
+
+func TestCallTxByPolling_ResponseLost(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{
+		Method:       TRANSACTION + "sendTransaction",
+		Times:        1,
+		DropResponse: true,
+	})
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 2)
+	receipt, err := rp.CallTxByPolling(TRANSACTION+"sendTransaction", tx)
+	assert.Nil(t, err)
+	assert.Equal(t, tx.GetHash(), receipt.TxHash)
+	assert.Equal(t, 1, srv.Requests(TRANSACTION+"sendTransaction"))
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,9 +11,9 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/hyperchain/gosdk/common"
+	"github.com/opentracing/opentracing-go"
 	"github.com/terasum/viper"
 )
 
@@ -74,11 +75,15 @@ type RPC struct {
 	reConnTime         int64
 	txVersion          string
 	im                 *inspectorManager
+	ctx                context.Context
+	transport          Transport
+	waiter             *ReceiptWaiter
+	journal            *Journal
 }
 
 type inspectorManager struct {
 	enable bool
-	key    account.Key
+	key    account.Signer
 }
 
 func (rpc *RPC) String() string {
@@ -104,12 +109,18 @@ type Config struct {
 	Namespace     string
 	ReConnectTime int64
 	JsonRPC       struct {
-		Nodes []string
-		Ports []string
+		Nodes    []string
+		Ports    []string
+		Selector string
+		Weights  []int
 	}
 	WebSocket struct {
 		Ports []string
 	}
+	Health struct {
+		FailureThreshold int64
+		ProbeInterval    int64
+	}
 	Polling struct {
 		ResendTime            int64
 		FirstPollingInterval  int64
@@ -147,6 +158,8 @@ type Config struct {
 	Tx struct {
 		Version string
 	}
+	// Tracer records spans of rpc, it can not be set in config file
+	Tracer opentracing.Tracer
 }
 
 func InitVip(vip *viper.Viper, config *Config) {
@@ -162,9 +175,21 @@ func InitVip(vip *viper.Viper, config *Config) {
 	vip.Set(common.JSONRPCPorts, config.JsonRPC.Ports)
 	logger.Debugf("[CONFIG]: %s = %v", common.JSONRPCPorts, config.JsonRPC.Ports)
 
+	vip.Set(common.JSONRPCSelector, config.JsonRPC.Selector)
+	logger.Debugf("[CONFIG]: %s = %v", common.JSONRPCSelector, config.JsonRPC.Selector)
+
+	vip.Set(common.JSONRPCWeights, config.JsonRPC.Weights)
+	logger.Debugf("[CONFIG]: %s = %v", common.JSONRPCWeights, config.JsonRPC.Weights)
+
 	vip.Set(common.WebSocketPorts, config.WebSocket.Ports)
 	logger.Debugf("[CONFIG]: %s = %v", common.WebSocketPorts, config.WebSocket.Ports)
 
+	vip.Set(common.HealthFailureThreshold, config.Health.FailureThreshold)
+	logger.Debugf("[CONFIG]: %s = %v", common.HealthFailureThreshold, config.Health.FailureThreshold)
+
+	vip.Set(common.HealthProbeInterval, config.Health.ProbeInterval)
+	logger.Debugf("[CONFIG]: %s = %v", common.HealthProbeInterval, config.Health.ProbeInterval)
+
 	vip.Set(common.PollingResendTime, config.Polling.ResendTime)
 	logger.Debugf("[CONFIG]: %s = %v", common.PollingResendTime, config.Polling.ResendTime)
 
@@ -252,6 +277,8 @@ func NewRPCWithConfig(config *Config) *RPC {
 		reConnTime:         config.ReConnectTime,
 		im:                 im,
 	}
+	rpc.hrm.interceptors = []Interceptor{authInterceptor(im)}
+	rpc.hrm.tracer = config.Tracer
 	txVersion, err := rpc.GetTxVersion()
 	if err != nil {
 		logger.Info("use config txVersion, for", err.Error())
@@ -327,6 +354,7 @@ func NewRPCWithPath(confRootPath string) *RPC {
 		reConnTime:         reConnTime,
 		im:                 im,
 	}
+	rpc.hrm.interceptors = []Interceptor{authInterceptor(im)}
 	//rpc := DefaultRPC(httpRequestManager.nodes...)
 	//	rpc.im = im
 	//	txVersion, err := rpc.GetTxVersion()
@@ -459,8 +487,10 @@ func DefaultRPC(nodes ...*Node) *RPC {
 		reConnTime:         DefaultReConnectTime,
 		hrm:                *defaultHTTPRequestManager(),
 		txVersion:          DefaultTxVersion,
+		im:                 &inspectorManager{},
 	}
 	rpc.hrm.nodes = nodes
+	rpc.hrm.interceptors = []Interceptor{authInterceptor(rpc.im)}
 
 	return rpc
 }
@@ -473,6 +503,10 @@ func (rpc *RPC) Namespace(ns string) *RPC {
 
 // Close close release goroutine and http connection
 func (rpc *RPC) Close() {
+	if rpc.transport != nil {
+		_ = rpc.transport.Close()
+	}
+	rpc.hrm.health.stop()
 	rpc.hrm.client.CloseIdleConnections()
 }
 
@@ -506,12 +540,40 @@ func (rpc *RPC) SecondPollTime(spt int64) *RPC {
 	return rpc
 }
 
-// ReConnTime setter
+// ReConnTime setter, it is the max interval(ms) between two probes of a bad node
 func (rpc *RPC) ReConnTime(rct int64) *RPC {
 	rpc.reConnTime = rct
+	rpc.hrm.reConnTime = rct
+	rpc.hrm.health.setReConnTime(rct)
+	return rpc
+}
+
+// FailureThreshold sets the number of consecutive failures that marks a node as bad
+func (rpc *RPC) FailureThreshold(threshold int64) *RPC {
+	rpc.hrm.health.setFailureThreshold(threshold)
 	return rpc
 }
 
+// ProbeInterval sets the first interval(ms) between two probes of a bad node,
+// the interval doubles after each failed probe until ReConnTime
+func (rpc *RPC) ProbeInterval(interval int64) *RPC {
+	rpc.hrm.health.setProbeInterval(interval, rpc.reConnTime)
+	return rpc
+}
+
+// OnNodeStateChange register handler which is called when a node goes down or comes back up,
+// handler is called synchronously so it should not block
+func (rpc *RPC) OnNodeStateChange(handler func(NodeEvent)) *RPC {
+	rpc.hrm.health.subscribe(handler)
+	return rpc
+}
+
+// NodeEvents return a channel receiving node up/down events, events are dropped if the channel is full.
+// The channel is closed by Close.
+func (rpc *RPC) NodeEvents(buffer int) <-chan NodeEvent {
+	return rpc.hrm.health.listen(buffer)
+}
+
 // Https use sets the https related options
 func (rpc *RPC) Https(tlscaPath, tlspeerCertPath, tlspeerPrivPath string) *RPC {
 	vip := viper.New()
@@ -521,6 +583,7 @@ func (rpc *RPC) Https(tlscaPath, tlspeerCertPath, tlspeerPrivPath string) *RPC {
 	vip.Set(common.SecurityTlspeerPriv, tlspeerPrivPath)
 
 	rpc.hrm.client = newHTTPClient(vip, ".")
+	rpc.hrm.health.client = rpc.hrm.client
 	rpc.hrm.isHTTP = true
 
 	for i := 0; i < len(rpc.hrm.nodes); i++ {
@@ -530,6 +593,20 @@ func (rpc *RPC) Https(tlscaPath, tlspeerCertPath, tlspeerPrivPath string) *RPC {
 	return rpc
 }
 
+// Selector sets the strategy used to choose node for each request
+func (rpc *RPC) Selector(selector NodeSelector) *RPC {
+	rpc.hrm.selector = selector
+	return rpc
+}
+
+// NodeWeights sets the weights of nodes in order, which is used by weighted selector
+func (rpc *RPC) NodeWeights(weights ...int) *RPC {
+	for i := 0; i < len(weights) && i < len(rpc.hrm.nodes); i++ {
+		rpc.hrm.nodes[i].setWeight(weights[i])
+	}
+	return rpc
+}
+
 func (rpc *RPC) AddNode(url, rpcPort, wsPort string) *RPC {
 	rpc.hrm.nodes = append(rpc.hrm.nodes, newNode(url, rpcPort, wsPort, rpc.hrm.isHTTP))
 
@@ -557,7 +634,6 @@ func (rpc *RPC) BindNodes(nodeIndexes ...int) (*RPC, error) {
 	}
 	proxy := *rpc
 	proxy.hrm.nodes = make([]*Node, len(nodeIndexes))
-	proxy.hrm.nodeIndex = 0
 
 	limit := len(rpc.hrm.nodes)
 	for i := 0; i < len(nodeIndexes); i++ {
@@ -569,28 +645,16 @@ func (rpc *RPC) BindNodes(nodeIndexes ...int) (*RPC, error) {
 	return &proxy, nil
 }
 
-// package method name and params to JsonRequest
+// package method name and params to JsonRequest,
+// inspector authentication is added by authInterceptor when the request is sent
 func (rpc *RPC) jsonRPC(method string, params ...interface{}) *JSONRequest {
-	req := &JSONRequest{
+	return &JSONRequest{
 		Method:    method,
 		Version:   JSONRPCVersion,
 		ID:        1,
 		Namespace: rpc.namespace,
 		Params:    params,
 	}
-	if rpc.im.enable {
-		auth := &Authentication{
-			Address:   rpc.im.key.GetAddress(),
-			Timestamp: time.Now().UnixNano(),
-		}
-		sig, err := sign(rpc.im.key, authNeedHash(auth), false, false)
-		if err != nil {
-			logger.Errorf("sign auth fail")
-		}
-		auth.Signature = sig
-		req.Auth = auth
-	}
-	return req
 }
 
 func authNeedHash(auth *Authentication) string {
@@ -606,52 +670,28 @@ func (rpc *RPC) call(method string, params ...interface{}) (json.RawMessage, Std
 
 // callWithReq is a function to get response origin data
 func (rpc *RPC) callWithReq(req *JSONRequest) (json.RawMessage, StdError) {
-	body, sysErr := json.Marshal(req)
-	if sysErr != nil {
-		return nil, NewSystemError(sysErr)
-	}
-
-	data, err := rpc.hrm.SyncRequest(body)
-	if err != nil {
-		return nil, err
-	}
-
-	var resp *JSONResponse
-	if sysErr = json.Unmarshal(data, &resp); sysErr != nil {
-		return nil, NewSystemError(sysErr)
-	}
-
-	if resp.Code != SuccessCode {
-		return nil, NewServerError(resp.Code, resp.Message)
-	}
-
-	return resp.Result, nil
+	span, rpc := rpc.startSpan(req.Method)
+	span.SetTag(TagMethod, req.Method)
+	data, err := rpc.getTransport().Call(rpc.getContext(), req)
+	finishSpan(span, err)
+	return data, err
 }
 
 // callWithSpecificUrl is a function to get response form specific url
 func (rpc *RPC) callWithSpecificURL(method string, url string, params ...interface{}) (json.RawMessage, StdError) {
-	req := rpc.jsonRPC(method, params...)
-
-	body, sysErr := json.Marshal(req)
-	if sysErr != nil {
-		return nil, NewSystemError(sysErr)
-	}
-
-	data, err := rpc.hrm.SyncRequestSpecificURL(body, url, GENERAL, nil, nil)
-	if err != nil {
-		return nil, err
+	span, rpc := rpc.startSpan(method)
+	span.SetTag(TagMethod, method)
+	call := &Call{
+		Request: rpc.jsonRPC(method, params...),
+		URL:     url,
 	}
-
-	var resp *JSONResponse
-	if sysErr = json.Unmarshal(data, &resp); sysErr != nil {
-		return nil, NewSystemError(sysErr)
+	err := rpc.hrm.do(rpc.getContext(), call, GENERAL, nil)
+	var data json.RawMessage
+	if err == nil {
+		data, err = call.result()
 	}
-
-	if resp.Code != SuccessCode {
-		return nil, NewServerError(resp.Code, resp.Message)
-	}
-
-	return resp.Result, nil
+	finishSpan(span, err)
+	return data, err
 }
 
 // Call call and get tx receipt directly without polling
@@ -667,33 +707,94 @@ func (rpc *RPC) Call(method string, param interface{}) (*TxReceipt, StdError) {
 	return &receipt, nil
 }
 
-// CallByPolling call and get tx receipt by polling
+// CallByPolling call and get tx receipt by polling, a *Transaction param is sent by CallTxByPolling
 func (rpc *RPC) CallByPolling(method string, param interface{}, isPrivateTx bool) (*TxReceipt, StdError) {
+	if transaction, ok := param.(*Transaction); ok {
+		return rpc.CallTxByPolling(method, transaction)
+	}
+	return rpc.callByPolling(method, param, isPrivateTx, "")
+}
+
+// CallTxByPolling sends transaction by method and gets its receipt by polling as SendTx does. The hash of
+// transaction is computed locally, so its receipt is queried before a resend, and a duplicate transaction
+// response means it has been accepted.
+func (rpc *RPC) CallTxByPolling(method string, transaction *Transaction) (*TxReceipt, StdError) {
+	transaction.txVersion = rpc.txVersion
+	return rpc.sendByPolling(method, transaction.Serialize(), transaction)
+}
+
+// callByPolling sends the transaction and polls its receipt, the transaction is resent if its receipt
+// is not found or a retryable error occurs. txHash is the hash computed locally, it may be empty.
+// Before a resend, the receipt of the hash is queried in case the transaction has been accepted but
+// its response is lost. A duplicate transaction response means the transaction has been accepted,
+// so its receipt is polled instead.
+func (rpc *RPC) callByPolling(method string, param interface{}, isPrivateTx bool, txHash string) (txReceipt *TxReceipt, err StdError) {
 	var (
 		req    *JSONRequest
 		data   json.RawMessage
 		hash   string
-		err    StdError
 		sysErr error
 	)
+	span, rpc := rpc.startSpan("CallByPolling")
+	span.SetTag(TagMethod, method)
+	if txHash != "" {
+		span.SetTag(TagTxHash, txHash)
+	}
+	defer func() {
+		finishSpan(span, err)
+	}()
+	// the transaction is journaled by the hash computed locally
+	journalHash := txHash
 	// if simulate is false, transaction need to resend
 	req = rpc.jsonRPC(method, param)
 	for i := int64(0); i < rpc.resTime; i++ {
+		if i > 0 {
+			rpc.hrm.observeRetry(method)
+			if txHash != "" {
+				receipt, innErr := rpc.GetTxReceipt(txHash, isPrivateTx)
+				if innErr == nil {
+					return receipt, nil
+				}
+				if !IsRetryable(innErr) {
+					return nil, innErr
+				}
+			}
+		}
+		span.SetTag(TagRetry, i)
 		if data, err = rpc.callWithReq(req); err != nil {
-			return nil, err
-		} else {
+			if errors.Is(err, ErrDuplicateTx) && txHash != "" {
+				logger.Infof("transaction %s has been accepted, poll its receipt", txHash)
+				data, _ = json.Marshal(txHash)
+				err = nil
+			} else if !IsRetryable(err) {
+				return nil, err
+			}
+		}
+		if err == nil {
 			if sysErr = json.Unmarshal(data, &hash); sysErr != nil {
 				return nil, NewSystemError(sysErr)
 			}
-			txReceipt, innErr, success := rpc.GetTxReceiptByPolling(hash, isPrivateTx)
+			if txHash != "" && !strings.EqualFold(hash, txHash) {
+				logger.Warningf("hash %s returned by node is not the same as %s computed locally", hash, txHash)
+			}
+			if rpc.journal != nil && journalHash != "" {
+				if jErr := rpc.journal.sent(journalHash); jErr != nil {
+					logger.Errorf("journal transaction %s failed: %v", journalHash, jErr)
+				}
+			}
+			txHash = hash
+			span.SetTag(TagTxHash, hash)
+			txReceipt, innErr, success := rpc.WaitReceipt(hash, isPrivateTx)
 			err = innErr
 			if success {
 				return txReceipt, err
 			}
 			continue
 		}
-		//if code is -9999 -32001 and -32006, we should sleep then resend
-		time.Sleep(time.Millisecond * time.Duration(rpc.firstPollInterval+rpc.secondPollInterval))
+		//if the error is retryable, such as -9999 -32001 and -32006, we should sleep then resend
+		if err = rpc.pollWait(rpc.firstPollInterval + rpc.secondPollInterval); err != nil {
+			return nil, err
+		}
 	}
 	return nil, NewRequestTimeoutError(errors.New("request time out"))
 }
@@ -712,40 +813,46 @@ func (rpc *RPC) GetTxVersion() (string, StdError) {
 }
 
 // GetTxReceiptByPolling get tx receipt by polling
-func (rpc *RPC) GetTxReceiptByPolling(txHash string, isPrivateTx bool) (*TxReceipt, StdError, bool) {
-	var (
-		err     StdError
-		receipt *TxReceipt
-	)
+func (rpc *RPC) GetTxReceiptByPolling(txHash string, isPrivateTx bool) (receipt *TxReceipt, err StdError, success bool) {
+	var polls int
 	txHash = chPrefix(txHash)
+	span, rpc := rpc.startSpan("GetTxReceiptByPolling")
+	span.SetTag(TagTxHash, txHash)
+	defer func() {
+		span.SetTag(TagPolls, polls)
+		finishSpan(span, err)
+		rpc.hrm.observePolling(polls, err)
+	}()
 
 	for j := int64(0); j < rpc.firstPollTime; j++ {
+		polls++
 		receipt, err = rpc.GetTxReceipt(txHash, isPrivateTx)
 		if err != nil {
-			if err.Code() == BalanceInsufficientCode {
+			if !IsRetryable(err) {
 				return nil, err, true
-			} else if err.Code() != DataNotExistCode && err.Code() != SystemBusyCode {
+			}
+			if err = rpc.pollWait(rpc.firstPollInterval); err != nil {
 				return nil, err, true
 			}
-			time.Sleep(time.Millisecond * time.Duration(rpc.firstPollInterval))
 		} else {
 			return receipt, nil, true
 		}
 	}
 	for j := int64(0); j < rpc.secondPollTime; j++ {
+		polls++
 		receipt, err = rpc.GetTxReceipt(txHash, isPrivateTx)
 		if err != nil {
-			if err.Code() == BalanceInsufficientCode {
+			if !IsRetryable(err) {
 				return nil, err, true
-			} else if err.Code() != DataNotExistCode && err.Code() != SystemBusyCode {
+			}
+			if err = rpc.pollWait(rpc.secondPollInterval); err != nil {
 				return nil, err, true
 			}
-			time.Sleep(time.Millisecond * time.Duration(rpc.secondPollInterval))
 		} else {
 			return receipt, nil, true
 		}
 	}
-	return nil, NewGetResponseError(errors.New("polling failure")), false
+	return nil, newPollingTimeoutError(), false
 }
 
 /*---------------------------------- node ----------------------------------*/
@@ -1805,11 +1912,11 @@ func (rpc *RPC) SendTx(transaction *Transaction) (*TxReceipt, StdError) {
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // SignAndSendTx 同步发送交易并签名
-func (rpc *RPC) SignAndSendTx(transaction *Transaction, key interface{}) (*TxReceipt, StdError) {
+func (rpc *RPC) SignAndSendTx(transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
 	transaction.txVersion = rpc.txVersion
 	transaction.Sign(key)
 	method := TRANSACTION + "sendTransaction"
@@ -1817,7 +1924,7 @@ func (rpc *RPC) SignAndSendTx(transaction *Transaction, key interface{}) (*TxRec
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 /*---------------------------------- contract ----------------------------------*/
@@ -1858,11 +1965,11 @@ func (rpc *RPC) DeployContract(transaction *Transaction) (*TxReceipt, StdError)
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // SignAndDeployContract Deploy contract rpc
-func (rpc *RPC) SignAndDeployContract(transaction *Transaction, key interface{}) (*TxReceipt, StdError) {
+func (rpc *RPC) SignAndDeployContract(transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
 	transaction.txVersion = rpc.txVersion
 	transaction.Sign(key)
 	var method string
@@ -1880,11 +1987,11 @@ func (rpc *RPC) SignAndDeployContract(transaction *Transaction, key interface{})
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // SignAndInvokeContract invoke contract rpc
-func (rpc *RPC) SignAndInvokeContract(transaction *Transaction, key interface{}) (*TxReceipt, StdError) {
+func (rpc *RPC) SignAndInvokeContract(transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
 	transaction.txVersion = rpc.txVersion
 	transaction.Sign(key)
 	var method string
@@ -1903,7 +2010,7 @@ func (rpc *RPC) SignAndInvokeContract(transaction *Transaction, key interface{})
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // Deprecated
@@ -1925,7 +2032,7 @@ func (rpc *RPC) InvokeContract(transaction *Transaction) (*TxReceipt, StdError)
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // Deprecated
@@ -1938,11 +2045,11 @@ func (rpc *RPC) ManageContractByVote(transaction *Transaction) (*TxReceipt, StdE
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // ManageContractByVote manage contract by vote rpc
-func (rpc *RPC) SignAndManageContractByVote(transaction *Transaction, key interface{}) (*TxReceipt, StdError) {
+func (rpc *RPC) SignAndManageContractByVote(transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
 	transaction.txVersion = rpc.txVersion
 	transaction.Sign(key)
 	method := CONTRACT + "manageContractByVote"
@@ -1952,7 +2059,7 @@ func (rpc *RPC) SignAndManageContractByVote(transaction *Transaction, key interf
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // GetCode 获取合约字节编码
@@ -2045,14 +2152,14 @@ func (rpc *RPC) MaintainContract(transaction *Transaction) (*TxReceipt, StdError
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // SignAndMaintainContract 管理合约 opcode
 // 1.升级合约
 // 2.冻结
 // 3.解冻
-func (rpc *RPC) SignAndMaintainContract(transaction *Transaction, key interface{}) (*TxReceipt, StdError) {
+func (rpc *RPC) SignAndMaintainContract(transaction *Transaction, key account.Signer) (*TxReceipt, StdError) {
 	transaction.txVersion = rpc.txVersion
 	transaction.Sign(key)
 	var method string
@@ -2066,7 +2173,7 @@ func (rpc *RPC) SignAndMaintainContract(transaction *Transaction, key interface{
 	if transaction.simulate {
 		return rpc.Call(method, param)
 	}
-	return rpc.CallByPolling(method, param, transaction.isPrivateTx)
+	return rpc.sendByPolling(method, param, transaction)
 }
 
 // GetContractStatus 获取合约状态
@@ -2257,11 +2364,7 @@ func (rpc *RPC) getTransactionsByFilter(filter *Filter, detail bool, mode int, m
 // GetWebSocketClient 获取WebSocket客户端
 func (rpc *RPC) GetWebSocketClient() *WebSocketClient {
 	once.Do(func() {
-		globalWebSocketClient = &WebSocketClient{
-			conns:   make(map[int]*connectionWrapper, len(rpc.hrm.nodes)),
-			hrm:     &rpc.hrm,
-			rwMutex: sync.RWMutex{},
-		}
+		globalWebSocketClient = newWebSocketClient(&rpc.hrm)
 	})
 
 	return globalWebSocketClient
@@ -2739,6 +2842,11 @@ func (rpc *RPC) SetAccount(key account.Key) {
 	rpc.im.key = key
 }
 
+// SetAccountSigner set account signer for sign request, the private key may be kept out of process
+func (rpc *RPC) SetAccountSigner(signer account.Signer) {
+	rpc.im.key = signer
+}
+
 // AddRoleForNode add roles for given address in node
 func (rpc *RPC) AddRoleForNode(address string, roles ...string) StdError {
 	method := AUTH + "addRole"
@@ -0,0 +1,61 @@
+package rpctest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// confTemplate is the config file of the sdk connecting to all nodes, with fast reconnection and polling
+const confTemplate = `namespace = "%s"
+reConnectTime = 100
+
+[jsonRPC]
+    nodes = [%s]
+    ports = [%s]
+
+[webSocket]
+    ports = [%s]
+
+[polling]
+    resendTime = 2
+    firstPollingInterval = 10
+    firstPollingTimes = 5
+    secondPollingInterval = 10
+    secondPollingTimes = 5
+
+[log]
+    log_level = "ERROR"
+    log_dir = "%s"
+`
+
+// ConfDir writes hpc.toml connecting to all nodes into a temporary directory and return the directory,
+// which is removed by Close. Reconnection and polling are fast and the logs are written into the directory,
+// so the tests of every package get the same RPC by rpc.NewRPCWithPath.
+func (s *Server) ConfDir() (string, error) {
+	dir, err := ioutil.TempDir("", "gosdk-rpctest")
+	if err != nil {
+		return "", err
+	}
+	conf := fmt.Sprintf(confTemplate, DefaultNamespace, quote(s.Hosts()), quote(s.Ports()), quote(s.Ports()),
+		filepath.ToSlash(filepath.Join(dir, "logs")))
+	if err = ioutil.WriteFile(filepath.Join(dir, "hpc.toml"), []byte(conf), 0600); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	s.confDirs = append(s.confDirs, dir)
+	return dir, nil
+}
+
+func quote(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = `"` + v + `"`
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,120 @@
+package rpctest
+
+import (
+	"net/http"
+	"time"
+)
+
+// AllNodes makes an injected fault apply to every node
+const AllNodes = -1
+
+// Fault describes how a node misbehaves on matched requests.
+// Delay is applied first, then the request is dropped, replied with StatusCode,
+// or replied with json rpc error Code, in that order. A fault with only Delay
+// set slows down the request which is then handled normally. DropResponse lets
+// the request be handled and drops the response.
+type Fault struct {
+	// Method is the json rpc method to match, empty matches every method
+	Method string
+	// Times is the number of requests the fault applies to, 0 means until ClearFaults is called
+	Times int
+	// Delay is the time to wait before replying
+	Delay time.Duration
+	// Drop closes the connection without reply
+	Drop bool
+	// DropResponse handles the request then closes the connection without reply, as if the response is lost
+	DropResponse bool
+	// StatusCode is the http status code to reply, e.g. http.StatusServiceUnavailable
+	StatusCode int
+	// Code and Message are the json rpc error to reply
+	Code    int
+	Message string
+}
+
+type faultRule struct {
+	node      int
+	fault     Fault
+	remaining int
+}
+
+// InjectFault makes the i-th node (AllNodes for every node) misbehave as f describes,
+// faults are matched in the order they are injected
+func (s *Server) InjectFault(i int, f Fault) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	s.faults = append(s.faults, &faultRule{
+		node:      i,
+		fault:     f,
+		remaining: f.Times,
+	})
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	s.faults = nil
+}
+
+// matchFault return the first fault matching the request and consumes one of its times
+func (s *Server) matchFault(nodeID int, method string) *Fault {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	for i, rule := range s.faults {
+		if rule.node != AllNodes && rule.node != nodeID {
+			continue
+		}
+		if rule.fault.Method != "" && rule.fault.Method != method {
+			continue
+		}
+		if rule.fault.Times > 0 {
+			rule.remaining--
+			if rule.remaining <= 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		f := rule.fault
+		return &f
+	}
+	return nil
+}
+
+// apply writes the faulty reply, it return false if the request should still be handled
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Delay > 0 {
+		timer := time.NewTimer(f.Delay)
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			timer.Stop()
+			return true
+		}
+	}
+	if f.Drop {
+		dropConn(w)
+		return true
+	}
+	if f.StatusCode != 0 {
+		w.WriteHeader(f.StatusCode)
+		return true
+	}
+	return false
+}
+
+// dropConn closes the connection of the request without reply
+func dropConn(w http.ResponseWriter) {
+	if hj, ok := w.(http.Hijacker); ok {
+		if conn, _, err := hj.Hijack(); err == nil {
+			_ = conn.Close()
+			return
+		}
+	}
+	panic(http.ErrAbortHandler)
+}
+
+func (f *Fault) message() string {
+	if f.Message != "" {
+		return f.Message
+	}
+	return "injected fault"
+}
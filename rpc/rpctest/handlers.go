@@ -0,0 +1,312 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var subscriptionCounter uint64
+
+func newSubscriptionID() string {
+	return "0x" + strconv.FormatUint(atomic.AddUint64(&subscriptionCounter, 1), 16) + "00000000"
+}
+
+func (s *Server) registerHandlers() {
+	s.handlers = map[string]handler{
+		"tx_getTransactionsVersion": s.getTransactionsVersion,
+		"tx_sendTransaction":        s.sendTx("sendTransaction", false),
+		"tx_getTransactionReceipt":  s.getTransactionReceipt,
+		"tx_getTransactionByHash":   s.getTransactionByHash,
+
+		"contract_deployContract":   s.sendTx("deployContract", false),
+		"contract_invokeContract":   s.sendTx("invokeContract", false),
+		"contract_maintainContract": s.sendTx("maintainContract", false),
+		"contract_getCode":          s.getCode,
+		"simulate_deployContract":   s.sendTx("deployContract", true),
+		"simulate_invokeContract":   s.sendTx("invokeContract", true),
+		"simulate_maintainContract": s.sendTx("maintainContract", true),
+
+		"block_latestBlock":      s.latestBlock,
+		"block_getBlockByNumber": s.getBlockByNumber,
+		"block_getBlockByHash":   s.getBlockByHash,
+		"block_getBlocks":        s.getBlocks,
+		"block_getChainHeight":   s.getChainHeight,
+		"block_getGenesisBlock":  s.getGenesisBlock,
+
+		"node_getNodes":    s.getNodes,
+		"node_getNodeHash": s.getNodeHash,
+	}
+}
+
+/*---------------------------------- tx ----------------------------------*/
+
+func (s *Server) getTransactionsVersion(n *node, params []json.RawMessage) (interface{}, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	return s.txVersion, nil
+}
+
+type txParam struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Value     int64  `json:"value"`
+	Payload   string `json:"payload"`
+	Timestamp int64  `json:"timestamp"`
+	Nonce     int64  `json:"nonce"`
+	Type      string `json:"type"`
+	Opcode    int    `json:"opcode"`
+	Extra     string `json:"extra"`
+	Signature string `json:"signature"`
+	Simulate  bool   `json:"simulate"`
+}
+
+// parseTx decodes the transaction param, numbers are kept as json.Number so that the hash is exact
+func parseTx(params []json.RawMessage) (*Tx, error) {
+	if len(params) == 0 {
+		return nil, &Error{Code: InvalidParamsCode, Message: "missing transaction param"}
+	}
+	var p txParam
+	if err := json.Unmarshal(params[0], &p); err != nil {
+		return nil, &Error{Code: InvalidParamsCode, Message: "invalid transaction param: " + err.Error()}
+	}
+	var m map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(params[0]))
+	d.UseNumber()
+	if err := d.Decode(&m); err != nil {
+		return nil, &Error{Code: InvalidParamsCode, Message: "invalid transaction param: " + err.Error()}
+	}
+	if p.From == "" {
+		return nil, &Error{Code: InvalidParamsCode, Message: "missing from address"}
+	}
+	return &Tx{
+		Hash:      txHash(m),
+		From:      strings.ToLower(p.From),
+		To:        strings.ToLower(p.To),
+		Value:     p.Value,
+		Payload:   p.Payload,
+		Timestamp: p.Timestamp,
+		Nonce:     p.Nonce,
+		VMType:    p.Type,
+		Opcode:    p.Opcode,
+		Extra:     p.Extra,
+		Signature: p.Signature,
+		Simulate:  p.Simulate,
+	}, nil
+}
+
+// sendTx handles transaction methods, the tx hash is returned unless the transaction is simulated,
+// in which case the receipt is returned directly
+func (s *Server) sendTx(method string, simulate bool) handler {
+	return func(n *node, params []json.RawMessage) (interface{}, error) {
+		tx, err := parseTx(params)
+		if err != nil {
+			return nil, err
+		}
+		tx.Simulate = tx.Simulate || simulate
+
+		s.rwMutex.RLock()
+		txVersion, hasher := s.txVersion, s.hasher
+		s.rwMutex.RUnlock()
+		if hasher != nil {
+			tx.Hash = hasher(tx)
+		}
+
+		receipt, stdErr := s.ledger.execute(method, tx, txVersion)
+		if stdErr != nil {
+			return nil, stdErr
+		}
+		if tx.Simulate {
+			return receipt, nil
+		}
+		return tx.Hash, nil
+	}
+}
+
+func (s *Server) getTransactionReceipt(n *node, params []json.RawMessage) (interface{}, error) {
+	hash, err := stringParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	receipt, stdErr := s.ledger.receipt(hash)
+	if stdErr != nil {
+		return nil, stdErr
+	}
+	return receipt, nil
+}
+
+func (s *Server) getTransactionByHash(n *node, params []json.RawMessage) (interface{}, error) {
+	hash, err := stringParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := s.ledger.transaction(hash)
+	if !ok {
+		return nil, &Error{Code: DataNotExistCode, Message: "transaction " + hash + " does not exist"}
+	}
+	return record.raw(), nil
+}
+
+/*---------------------------------- contract ----------------------------------*/
+
+func (s *Server) getCode(n *node, params []json.RawMessage) (interface{}, error) {
+	address, err := stringParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	code, stdErr := s.ledger.code(strings.ToLower(address))
+	if stdErr != nil {
+		return nil, stdErr
+	}
+	return code, nil
+}
+
+/*---------------------------------- block ----------------------------------*/
+
+func (s *Server) latestBlock(n *node, params []json.RawMessage) (interface{}, error) {
+	b, err := s.ledger.blockByNumber(0)
+	if err != nil {
+		return nil, err
+	}
+	return b.raw(true), nil
+}
+
+func (s *Server) getBlockByNumber(n *node, params []json.RawMessage) (interface{}, error) {
+	if len(params) == 0 {
+		return nil, &Error{Code: InvalidParamsCode, Message: "missing block number"}
+	}
+	number, err := blockNumber(params[0])
+	if err != nil {
+		return nil, err
+	}
+	b, stdErr := s.ledger.blockByNumber(number)
+	if stdErr != nil {
+		return nil, stdErr
+	}
+	return b.raw(boolParam(params, 1)), nil
+}
+
+func (s *Server) getBlockByHash(n *node, params []json.RawMessage) (interface{}, error) {
+	hash, err := stringParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, stdErr := s.ledger.blockByHash(hash)
+	if stdErr != nil {
+		return nil, stdErr
+	}
+	return b.raw(boolParam(params, 1)), nil
+}
+
+func (s *Server) getBlocks(n *node, params []json.RawMessage) (interface{}, error) {
+	var p struct {
+		From    json.RawMessage `json:"from"`
+		To      json.RawMessage `json:"to"`
+		IsPlain bool            `json:"isPlain"`
+	}
+	if len(params) == 0 || json.Unmarshal(params[0], &p) != nil {
+		return nil, &Error{Code: InvalidParamsCode, Message: "invalid block range"}
+	}
+	from, err := blockNumber(p.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := blockNumber(p.To)
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		to = s.ledger.height()
+	}
+	var blocks []interface{}
+	// blocks are returned from the newest to the oldest as hyperchain does
+	for number := to; number >= from && number > 0; number-- {
+		b, stdErr := s.ledger.blockByNumber(number)
+		if stdErr != nil {
+			return nil, stdErr
+		}
+		blocks = append(blocks, b.raw(p.IsPlain))
+	}
+	return blocks, nil
+}
+
+func (s *Server) getChainHeight(n *node, params []json.RawMessage) (interface{}, error) {
+	height := s.ledger.height()
+	if height == 0 {
+		return nil, &Error{Code: NoBlockGeneratedCode, Message: "there is no block generated"}
+	}
+	return toHex(height), nil
+}
+
+func (s *Server) getGenesisBlock(n *node, params []json.RawMessage) (interface{}, error) {
+	return toHex(1), nil
+}
+
+/*---------------------------------- node ----------------------------------*/
+
+func (s *Server) getNodes(n *node, params []json.RawMessage) (interface{}, error) {
+	nodes := make([]map[string]interface{}, len(s.nodes))
+	for i, other := range s.nodes {
+		host, port := other.hostPort()
+		nodes[i] = map[string]interface{}{
+			"status":    0,
+			"ip":        host,
+			"port":      port,
+			"id":        i + 1,
+			"isPrimary": i == 0,
+			"delay":     0,
+			"isvp":      true,
+			"peerType":  "VP",
+			"namespace": DefaultNamespace,
+			"hash":      other.hash(),
+			"hostname":  "node" + strconv.Itoa(i+1),
+		}
+	}
+	return nodes, nil
+}
+
+func (s *Server) getNodeHash(n *node, params []json.RawMessage) (interface{}, error) {
+	return n.hash(), nil
+}
+
+func (n *node) hash() string {
+	return hashOf("node", strconv.Itoa(n.id))[2:]
+}
+
+/*---------------------------------- params ----------------------------------*/
+
+func stringParam(params []json.RawMessage, i int) (string, error) {
+	var str string
+	if len(params) <= i || json.Unmarshal(params[i], &str) != nil {
+		return "", &Error{Code: InvalidParamsCode, Message: "param " + strconv.Itoa(i) + " should be a string"}
+	}
+	return str, nil
+}
+
+func boolParam(params []json.RawMessage, i int) bool {
+	var b bool
+	if len(params) > i {
+		_ = json.Unmarshal(params[i], &b)
+	}
+	return b
+}
+
+// blockNumber parses number, hex or decimal string, or "latest" which returns 0
+func blockNumber(raw json.RawMessage) (uint64, error) {
+	var number uint64
+	if json.Unmarshal(raw, &number) == nil {
+		return number, nil
+	}
+	var str string
+	if json.Unmarshal(raw, &str) == nil {
+		if str == "latest" {
+			return 0, nil
+		}
+		if number, err := strconv.ParseUint(str, 0, 64); err == nil {
+			return number, nil
+		}
+	}
+	return 0, &Error{Code: InvalidParamsCode, Message: "invalid block number " + string(raw)}
+}
@@ -0,0 +1,378 @@
+package rpctest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// contract status of maintain operations
+const (
+	contractNormal    = "normal"
+	contractFrozen    = "frozen"
+	contractDestroyed = "destroyed"
+)
+
+// Tx is a transaction received by Server
+type Tx struct {
+	Hash      string
+	From      string
+	To        string
+	Value     int64
+	Payload   string
+	Timestamp int64
+	Nonce     int64
+	VMType    string
+	Opcode    int
+	Extra     string
+	Signature string
+	Simulate  bool
+}
+
+// Receipt is the receipt of a transaction, the json is the same as hyperchain's
+type Receipt struct {
+	TxHash          string        `json:"txHash"`
+	ContractAddress string        `json:"contractAddress"`
+	Ret             string        `json:"ret"`
+	Log             []interface{} `json:"log"`
+	VMType          string        `json:"vmType"`
+	Version         string        `json:"version"`
+	Valid           bool          `json:"valid"`
+}
+
+// TxHasher computes the hash of a transaction, the hash should be unique for every transaction
+type TxHasher func(tx *Tx) string
+
+// ContractHandler executes invoke transactions, ret is the hex encoded return value.
+// If err is not nil the transaction fails with ContractInvokeErrorCode and err as message.
+type ContractHandler func(tx *Tx) (ret string, err error)
+
+type block struct {
+	number     uint64
+	hash       string
+	parentHash string
+	writeTime  int64
+	txs        []*txRecord
+}
+
+type txRecord struct {
+	tx      *Tx
+	block   *block
+	index   int
+	receipt *Receipt
+	err     *Error
+}
+
+type contract struct {
+	code   string
+	status string
+}
+
+// ledger is the chain shared by all nodes, it mines one block for every transaction
+type ledger struct {
+	rwMutex      sync.RWMutex
+	blocks       []*block
+	txs          map[string]*txRecord
+	contracts    map[string]*contract
+	handler      ContractHandler
+	receiptDelay int
+	pending      map[string]int
+	onBlock      func(b *block)
+}
+
+func newLedger() *ledger {
+	return &ledger{
+		txs:       make(map[string]*txRecord),
+		contracts: make(map[string]*contract),
+		pending:   make(map[string]int),
+	}
+}
+
+// HandleContract set the handler executing invoke transactions,
+// by default invoke returns empty ret
+func (s *Server) HandleContract(handler ContractHandler) {
+	s.ledger.rwMutex.Lock()
+	defer s.ledger.rwMutex.Unlock()
+	s.ledger.handler = handler
+}
+
+// SetReceiptDelay makes the receipt of a new transaction available after it is queried n times,
+// the queries before reply DataNotExistCode as a real node does before the block is committed
+func (s *Server) SetReceiptDelay(n int) {
+	s.ledger.rwMutex.Lock()
+	defer s.ledger.rwMutex.Unlock()
+	s.ledger.receiptDelay = n
+}
+
+// Height return the number of the latest block, 0 if no block is mined
+func (s *Server) Height() uint64 {
+	s.ledger.rwMutex.RLock()
+	defer s.ledger.rwMutex.RUnlock()
+	return uint64(len(s.ledger.blocks))
+}
+
+// Transactions return all committed transactions in block order
+func (s *Server) Transactions() []*Tx {
+	s.ledger.rwMutex.RLock()
+	defer s.ledger.rwMutex.RUnlock()
+	var txs []*Tx
+	for _, b := range s.ledger.blocks {
+		for _, record := range b.txs {
+			txs = append(txs, record.tx)
+		}
+	}
+	return txs
+}
+
+// execute runs tx and commits it in a new block unless tx.Simulate is set.
+// method is one of the send/deploy/invoke/maintain json rpc methods without namespace prefix.
+func (l *ledger) execute(method string, tx *Tx, txVersion string) (*Receipt, *Error) {
+	l.rwMutex.Lock()
+	record, err := l.executeLocked(method, tx, txVersion)
+	if err != nil || tx.Simulate {
+		l.rwMutex.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return record.receipt, record.err
+	}
+
+	parent := ""
+	if len(l.blocks) > 0 {
+		parent = l.blocks[len(l.blocks)-1].hash
+	}
+	b := &block{
+		number:     uint64(len(l.blocks) + 1),
+		hash:       hashOf(parent, tx.Hash),
+		parentHash: parent,
+		writeTime:  tx.Timestamp,
+		txs:        []*txRecord{record},
+	}
+	record.block = b
+	l.blocks = append(l.blocks, b)
+	l.txs[tx.Hash] = record
+	l.pending[tx.Hash] = l.receiptDelay
+	onBlock := l.onBlock
+	l.rwMutex.Unlock()
+
+	if onBlock != nil {
+		onBlock(b)
+	}
+	return record.receipt, nil
+}
+
+func (l *ledger) executeLocked(method string, tx *Tx, txVersion string) (*txRecord, *Error) {
+	if !tx.Simulate {
+		if _, ok := l.txs[tx.Hash]; ok {
+			return nil, &Error{Code: DuplicateTransactionsCode, Message: "duplicate transaction " + tx.Hash}
+		}
+	}
+
+	receipt := &Receipt{
+		TxHash:  tx.Hash,
+		Ret:     "0x0",
+		Log:     []interface{}{},
+		VMType:  tx.VMType,
+		Version: txVersion,
+		Valid:   true,
+	}
+	record := &txRecord{tx: tx, receipt: receipt}
+	if receipt.VMType == "" {
+		receipt.VMType = "EVM"
+	}
+
+	switch method {
+	case "deployContract":
+		receipt.ContractAddress = addressOf(tx.From, tx.Nonce)
+		if !tx.Simulate {
+			l.contracts[receipt.ContractAddress] = &contract{code: tx.Payload, status: contractNormal}
+		}
+	case "invokeContract":
+		c, ok := l.contracts[tx.To]
+		if !ok || c.status != contractNormal {
+			record.err = &Error{Code: ContractInvokeErrorCode, Message: "contract " + tx.To + " is not available"}
+			break
+		}
+		if l.handler != nil {
+			ret, err := l.handler(tx)
+			if err != nil {
+				record.err = &Error{Code: ContractInvokeErrorCode, Message: err.Error()}
+				break
+			}
+			receipt.Ret = ret
+		}
+	case "maintainContract":
+		c, ok := l.contracts[tx.To]
+		if !ok || c.status == contractDestroyed {
+			record.err = &Error{Code: ContractPermissionErrorCode, Message: "contract " + tx.To + " does not exist"}
+			break
+		}
+		if tx.Simulate {
+			break
+		}
+		switch tx.Opcode {
+		case 1:
+			c.code = tx.Payload
+		case 2:
+			c.status = contractFrozen
+		case 3:
+			c.status = contractNormal
+		case 5:
+			c.status = contractDestroyed
+		}
+		receipt.ContractAddress = tx.To
+	}
+	if record.err != nil {
+		record.receipt = nil
+	}
+	return record, nil
+}
+
+// receipt return the receipt of committed tx, the DataNotExistCode error is returned
+// while tx is not committed or the receipt is delayed
+func (l *ledger) receipt(hash string) (*Receipt, *Error) {
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+	record, ok := l.txs[hash]
+	if !ok || l.pending[hash] > 0 {
+		if ok {
+			l.pending[hash]--
+		}
+		return nil, &Error{Code: DataNotExistCode, Message: "receipt of " + hash + " does not exist"}
+	}
+	return record.receipt, record.err
+}
+
+func (l *ledger) transaction(hash string) (*txRecord, bool) {
+	l.rwMutex.RLock()
+	defer l.rwMutex.RUnlock()
+	record, ok := l.txs[hash]
+	return record, ok
+}
+
+// blockByNumber return block of number, 0 means the latest block
+func (l *ledger) blockByNumber(number uint64) (*block, *Error) {
+	l.rwMutex.RLock()
+	defer l.rwMutex.RUnlock()
+	if len(l.blocks) == 0 {
+		return nil, &Error{Code: NoBlockGeneratedCode, Message: "there is no block generated"}
+	}
+	if number == 0 {
+		return l.blocks[len(l.blocks)-1], nil
+	}
+	if number > uint64(len(l.blocks)) {
+		return nil, &Error{Code: DataNotExistCode, Message: fmt.Sprintf("block %d does not exist", number)}
+	}
+	return l.blocks[number-1], nil
+}
+
+func (l *ledger) blockByHash(hash string) (*block, *Error) {
+	l.rwMutex.RLock()
+	defer l.rwMutex.RUnlock()
+	for _, b := range l.blocks {
+		if b.hash == hash {
+			return b, nil
+		}
+	}
+	return nil, &Error{Code: DataNotExistCode, Message: "block " + hash + " does not exist"}
+}
+
+func (l *ledger) code(address string) (string, *Error) {
+	l.rwMutex.RLock()
+	defer l.rwMutex.RUnlock()
+	c, ok := l.contracts[address]
+	if !ok || c.status == contractDestroyed {
+		return "", &Error{Code: DataNotExistCode, Message: "contract " + address + " does not exist"}
+	}
+	return c.code, nil
+}
+
+func (l *ledger) height() uint64 {
+	l.rwMutex.RLock()
+	defer l.rwMutex.RUnlock()
+	return uint64(len(l.blocks))
+}
+
+// raw return the json object of block, transactions are omitted if isPlain is true
+func (b *block) raw(isPlain bool) map[string]interface{} {
+	m := map[string]interface{}{
+		"version":    DefaultTxVersion,
+		"number":     toHex(b.number),
+		"hash":       b.hash,
+		"parentHash": b.parentHash,
+		"writeTime":  b.writeTime,
+		"avgTime":    "0x0",
+		"txcounts":   toHex(uint64(len(b.txs))),
+		"merkleRoot": b.hash,
+	}
+	if !isPlain {
+		txs := make([]map[string]interface{}, len(b.txs))
+		for i, record := range b.txs {
+			txs[i] = record.raw()
+		}
+		m["transactions"] = txs
+	}
+	return m
+}
+
+// raw return the json object of transaction
+func (r *txRecord) raw() map[string]interface{} {
+	m := map[string]interface{}{
+		"version":     DefaultTxVersion,
+		"hash":        r.tx.Hash,
+		"blockNumber": toHex(r.block.number),
+		"blockHash":   r.block.hash,
+		"txIndex":     toHex(uint64(r.index)),
+		"from":        r.tx.From,
+		"to":          r.tx.To,
+		"amount":      strconv.FormatInt(r.tx.Value, 10),
+		"timestamp":   r.tx.Timestamp,
+		"nonce":       r.tx.Nonce,
+		"extra":       r.tx.Extra,
+		"executeTime": "0x0",
+		"payload":     r.tx.Payload,
+		"signature":   r.tx.Signature,
+
+		"blockTimestamp": r.block.writeTime,
+		"blockWriteTime": r.block.writeTime,
+	}
+	if r.err != nil {
+		m["invalid"] = true
+		m["invalidMsg"] = r.err.Message
+	}
+	return m
+}
+
+// txHash is the hash of the json of tx params, simulate flag is excluded,
+// so the same transaction always has the same hash
+func txHash(param map[string]interface{}) string {
+	cp := make(map[string]interface{}, len(param))
+	for k, v := range param {
+		if k != "simulate" {
+			cp[k] = v
+		}
+	}
+	data, _ := json.Marshal(cp)
+	sum := sha256.Sum256(data)
+	return "0x" + hex.EncodeToString(sum[:])
+}
+
+// addressOf return the address of contract deployed by from with nonce
+func addressOf(from string, nonce int64) string {
+	return hashOf(from, strconv.FormatInt(nonce, 10))[:42]
+}
+
+func hashOf(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		_, _ = h.Write([]byte(part))
+	}
+	return "0x" + hex.EncodeToString(h.Sum(nil))
+}
+
+func toHex(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
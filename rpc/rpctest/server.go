@@ -0,0 +1,547 @@
+// Package rpctest provides an in-process hyperchain node for unit tests of the sdk.
+//
+// A Server serves json rpc over http and websocket on the same port of every node,
+// nodes share one in-memory ledger which mines a block for every transaction, so
+// transaction hashes, contract addresses and receipts only depend on the transaction.
+// Faults such as delays, http errors and json rpc errors can be injected per node.
+//
+// The package does not import rpc, so it can be used by the tests of rpc itself:
+//
+//	srv := rpctest.NewServer(4)
+//	defer srv.Close()
+//	dir, err := srv.ConfDir()
+//	hrpc := rpc.NewRPCWithPath(dir)
+package rpctest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultTxVersion is the tx version returned by tx_getTransactionsVersion
+	DefaultTxVersion = "2.4"
+	// DefaultNamespace is the namespace of the chain
+	DefaultNamespace = "global"
+)
+
+// json rpc error codes replied by Server, they are the same as the codes in package rpc
+const (
+	InvalidRequestCode          = -32600
+	MethodNotExistOrInvalidCode = -32601
+	InvalidParamsCode           = -32602
+	DataNotExistCode            = -32001
+	ContractInvokeErrorCode     = -32005
+	DuplicateTransactionsCode   = -32007
+	ContractPermissionErrorCode = -32008
+	NoBlockGeneratedCode        = -32011
+)
+
+// JSONRequest is a json rpc request received by Server
+type JSONRequest struct {
+	Method    string            `json:"method"`
+	Version   string            `json:"jsonrpc"`
+	ID        json.RawMessage   `json:"id"`
+	Namespace string            `json:"namespace"`
+	Params    []json.RawMessage `json:"params"`
+	Auth      *Authentication   `json:"auth"`
+}
+
+// Authentication is the inspector authentication of a request
+type Authentication struct {
+	Timestamp int64  `json:"timestamp"`
+	Address   string `json:"address"`
+	Signature string `json:"signature"`
+}
+
+// JSONResponse is a json rpc response replied by Server
+type JSONResponse struct {
+	Version   string      `json:"jsonrpc"`
+	ID        interface{} `json:"id,omitempty"`
+	Result    interface{} `json:"result,omitempty"`
+	Namespace string      `json:"namespace"`
+	Code      int         `json:"code"`
+	Message   string      `json:"message"`
+}
+
+// Error is a json rpc error, handlers return it to reply a specific code
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// handler handles a json rpc call, result is marshaled as the result of response
+type handler func(node *node, params []json.RawMessage) (interface{}, error)
+
+// Server is a hyperchain network of several nodes listening on localhost
+type Server struct {
+	ledger   *ledger
+	nodes    []*node
+	handlers map[string]handler
+	upgrader websocket.Upgrader
+
+	rwMutex   sync.RWMutex
+	txVersion string
+	faults    []*faultRule
+	requests  map[string][]int
+	headers   map[string]http.Header
+	hasher    TxHasher
+	confDirs  []string
+	auth      bool
+}
+
+// NewServer starts a network of nodeNum nodes, nodeNum is at least 1
+func NewServer(nodeNum int) *Server {
+	if nodeNum <= 0 {
+		nodeNum = 1
+	}
+	s := &Server{
+		ledger:    newLedger(),
+		txVersion: DefaultTxVersion,
+		requests:  make(map[string][]int),
+		headers:   make(map[string]http.Header),
+		upgrader: websocket.Upgrader{
+			// sdk sends a fake origin as hyperchain does not check it
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+	s.ledger.onBlock = s.notifyBlock
+	s.registerHandlers()
+	for i := 0; i < nodeNum; i++ {
+		n := &node{
+			id:     i,
+			server: s,
+			subs:   make(map[string]*subscription),
+		}
+		n.httpServer = httptest.NewServer(http.HandlerFunc(n.ServeHTTP))
+		s.nodes = append(s.nodes, n)
+	}
+	return s
+}
+
+// Close shuts down all nodes, closes websocket connections and removes the directories of ConfDir
+func (s *Server) Close() {
+	for _, n := range s.nodes {
+		n.close()
+	}
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	for _, dir := range s.confDirs {
+		_ = os.RemoveAll(dir)
+	}
+	s.confDirs = nil
+}
+
+// CloseWebSockets closes the websocket connections of all nodes, as if they are broken,
+// the nodes keep serving http and new websocket connections
+func (s *Server) CloseWebSockets() {
+	for _, n := range s.nodes {
+		n.closeWebSockets()
+	}
+}
+
+// NodeNum return the number of nodes
+func (s *Server) NodeNum() int {
+	return len(s.nodes)
+}
+
+// URL return the http url of the i-th node, i starts from 0
+func (s *Server) URL(i int) string {
+	return s.nodes[i].httpServer.URL
+}
+
+// Hosts return the hosts of all nodes, it can be used as rpc.Config.JsonRPC.Nodes
+func (s *Server) Hosts() []string {
+	hosts := make([]string, len(s.nodes))
+	for i, n := range s.nodes {
+		hosts[i], _ = n.hostPort()
+	}
+	return hosts
+}
+
+// Ports return the ports of all nodes, it can be used as both
+// rpc.Config.JsonRPC.Ports and rpc.Config.WebSocket.Ports
+func (s *Server) Ports() []string {
+	ports := make([]string, len(s.nodes))
+	for i, n := range s.nodes {
+		_, ports[i] = n.hostPort()
+	}
+	return ports
+}
+
+// SetTxVersion set the tx version returned by tx_getTransactionsVersion
+func (s *Server) SetTxVersion(version string) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	s.txVersion = version
+}
+
+// SetTxHasher set the function computing transaction hash, so the hash can be the same as the one
+// computed by client. By default the hash is the sha256 of the transaction param.
+func (s *Server) SetTxHasher(hasher TxHasher) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	s.hasher = hasher
+}
+
+// RequireAuth makes nodes reject the http requests without inspector authentication, as the nodes
+// enabling inspector do, the signature is not verified
+func (s *Server) RequireAuth(require bool) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	s.auth = require
+}
+
+func (s *Server) authRequired() bool {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	return s.auth
+}
+
+// Requests return the number of calls of method received by all nodes, including the failed ones
+func (s *Server) Requests(method string) int {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	total := 0
+	for _, count := range s.requests[method] {
+		total += count
+	}
+	return total
+}
+
+// NodeRequests return the number of calls of method received by the i-th node
+func (s *Server) NodeRequests(i int, method string) int {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	if counts := s.requests[method]; i < len(counts) {
+		return counts[i]
+	}
+	return 0
+}
+
+// LastHeader return the header of the last http request of method, nil if there is none.
+// Calls over websocket are not recorded.
+func (s *Server) LastHeader(method string) http.Header {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	return s.headers[method]
+}
+
+func (s *Server) countRequest(nodeID int, method string, header http.Header) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	if header != nil {
+		s.headers[method] = header
+	}
+	counts := s.requests[method]
+	if counts == nil {
+		counts = make([]int, len(s.nodes))
+		s.requests[method] = counts
+	}
+	counts[nodeID]++
+}
+
+// notifyBlock pushes the new block to block subscribers of all nodes
+func (s *Server) notifyBlock(b *block) {
+	for _, n := range s.nodes {
+		n.notify("block", func(sub *subscription) interface{} {
+			return b.raw(!sub.blockInfo)
+		})
+	}
+}
+
+/*---------------------------------- node ----------------------------------*/
+
+type node struct {
+	id         int
+	server     *Server
+	httpServer *httptest.Server
+
+	mutex sync.Mutex
+	conns []*wsConn
+	subs  map[string]*subscription
+}
+
+func (n *node) hostPort() (string, string) {
+	host, port, _ := net.SplitHostPort(n.httpServer.Listener.Addr().String())
+	return host, port
+}
+
+func (n *node) close() {
+	n.closeWebSockets()
+	n.httpServer.Close()
+}
+
+func (n *node) closeWebSockets() {
+	n.mutex.Lock()
+	conns := n.conns
+	n.conns = nil
+	n.mutex.Unlock()
+	for _, c := range conns {
+		_ = c.conn.Close()
+	}
+}
+
+func (n *node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		n.serveWebSocket(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var reqs []*JSONRequest
+	batch := len(body) > 0 && body[0] == '['
+	if batch {
+		err = json.Unmarshal(body, &reqs)
+	} else {
+		var req JSONRequest
+		err = json.Unmarshal(body, &req)
+		reqs = append(reqs, &req)
+	}
+	if err == nil && len(reqs) == 0 {
+		err = errors.New("empty batch")
+	}
+	if err != nil {
+		writeJSON(w, &JSONResponse{Version: "2.0", Namespace: DefaultNamespace, Code: InvalidRequestCode, Message: "invalid request: " + err.Error()})
+		return
+	}
+
+	for _, req := range reqs {
+		n.server.countRequest(n.id, req.Method, r.Header)
+	}
+	// fault of the first call applies to the whole http request
+	fault := n.server.matchFault(n.id, reqs[0].Method)
+	if fault != nil {
+		if fault.apply(w, r) {
+			return
+		}
+		if fault.Code != 0 {
+			writeJSON(w, n.errorResponse(reqs[0], &Error{Code: fault.Code, Message: fault.message()}))
+			return
+		}
+	}
+
+	resps := make([]*JSONResponse, len(reqs))
+	authRequired := n.server.authRequired()
+	for i, req := range reqs {
+		if authRequired && (req.Auth == nil || req.Auth.Signature == "") {
+			resps[i] = n.errorResponse(req, &Error{Code: InvalidRequestCode, Message: "missing inspector authentication"})
+			continue
+		}
+		resps[i] = n.handle(req)
+	}
+	if fault != nil && fault.DropResponse {
+		dropConn(w)
+		return
+	}
+	if batch {
+		writeJSON(w, resps)
+	} else {
+		writeJSON(w, resps[0])
+	}
+}
+
+// handle calls the handler of req and builds the response
+func (n *node) handle(req *JSONRequest) *JSONResponse {
+	h, ok := n.server.handlers[req.Method]
+	if !ok {
+		return n.errorResponse(req, &Error{Code: MethodNotExistOrInvalidCode, Message: "the method " + req.Method + " does not exist/is not available"})
+	}
+	result, err := h(n, req.Params)
+	if err != nil {
+		return n.errorResponse(req, err)
+	}
+	return &JSONResponse{
+		Version:   "2.0",
+		ID:        req.ID,
+		Namespace: namespaceOf(req),
+		Code:      0,
+		Message:   "SUCCESS",
+		Result:    result,
+	}
+}
+
+func (n *node) errorResponse(req *JSONRequest, err error) *JSONResponse {
+	resp := &JSONResponse{
+		Version:   "2.0",
+		ID:        req.ID,
+		Namespace: namespaceOf(req),
+		Code:      InvalidParamsCode,
+		Message:   err.Error(),
+	}
+	if e, ok := err.(*Error); ok {
+		resp.Code = e.Code
+	}
+	return resp
+}
+
+func namespaceOf(req *JSONRequest) string {
+	if req.Namespace == "" {
+		return DefaultNamespace
+	}
+	return req.Namespace
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+/*---------------------------------- websocket ----------------------------------*/
+
+type wsConn struct {
+	mutex sync.Mutex
+	conn  *websocket.Conn
+}
+
+func (c *wsConn) writeJSON(v interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return c.conn.WriteJSON(v)
+}
+
+type subscription struct {
+	id        string
+	event     string
+	blockInfo bool
+	conn      *wsConn
+}
+
+func (n *node) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := n.server.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &wsConn{conn: conn}
+	n.mutex.Lock()
+	n.conns = append(n.conns, c)
+	n.mutex.Unlock()
+
+	defer n.removeConn(c)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req JSONRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			continue
+		}
+		n.server.countRequest(n.id, req.Method, nil)
+
+		var resp *JSONResponse
+		switch req.Method {
+		case "sub_subscribe":
+			resp = n.subscribe(c, &req)
+		case "sub_unsubscribe":
+			resp = n.unsubscribe(&req)
+		default:
+			resp = n.handle(&req)
+		}
+		if err := c.writeJSON(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (n *node) removeConn(c *wsConn) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for id, sub := range n.subs {
+		if sub.conn == c {
+			delete(n.subs, id)
+		}
+	}
+	for i := range n.conns {
+		if n.conns[i] == c {
+			n.conns = append(n.conns[:i], n.conns[i+1:]...)
+			break
+		}
+	}
+	_ = c.conn.Close()
+}
+
+func (n *node) subscribe(c *wsConn, req *JSONRequest) *JSONResponse {
+	var event string
+	if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &event) != nil {
+		return n.errorResponse(req, &Error{Code: InvalidParamsCode, Message: "missing event type"})
+	}
+	if event != "block" {
+		return n.errorResponse(req, &Error{Code: InvalidParamsCode, Message: "unsupported event " + event})
+	}
+	sub := &subscription{
+		id:    newSubscriptionID(),
+		event: event,
+		conn:  c,
+	}
+	if len(req.Params) > 1 {
+		_ = json.Unmarshal(req.Params[1], &sub.blockInfo)
+	}
+
+	n.mutex.Lock()
+	n.subs[sub.id] = sub
+	n.mutex.Unlock()
+
+	return &JSONResponse{Version: "2.0", ID: req.ID, Namespace: namespaceOf(req), Message: "SUCCESS", Result: sub.id}
+}
+
+func (n *node) unsubscribe(req *JSONRequest) *JSONResponse {
+	var id string
+	if len(req.Params) > 0 {
+		_ = json.Unmarshal(req.Params[0], &id)
+	}
+	n.mutex.Lock()
+	_, ok := n.subs[id]
+	delete(n.subs, id)
+	n.mutex.Unlock()
+
+	return &JSONResponse{Version: "2.0", ID: req.ID, Namespace: namespaceOf(req), Message: "SUCCESS", Result: ok}
+}
+
+// notify sends the data built by dataFn to every subscription of event
+func (n *node) notify(event string, dataFn func(sub *subscription) interface{}) {
+	n.mutex.Lock()
+	var subs []*subscription
+	for _, sub := range n.subs {
+		if sub.event == event {
+			subs = append(subs, sub)
+		}
+	}
+	n.mutex.Unlock()
+
+	for _, sub := range subs {
+		_ = sub.conn.writeJSON(&JSONResponse{
+			Version:   "2.0",
+			Namespace: DefaultNamespace,
+			Result: map[string]interface{}{
+				"event":        event,
+				"subscription": sub.id,
+				"data":         dataFn(sub),
+			},
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+type testResponse struct {
+	ID      int             `json:"id"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+func call(t *testing.T, url, method string, params ...interface{}) *testResponse {
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc":   "2.0",
+		"id":        1,
+		"namespace": DefaultNamespace,
+		"method":    method,
+		"params":    params,
+	})
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var r testResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	return &r
+}
+
+func testTx(nonce int64) map[string]interface{} {
+	return map[string]interface{}{
+		"from":      "0x000f1a7a08ccc48e5d30f80850cf1cf283aa3abd",
+		"to":        "0x6201cb0448964ac597faf6fdf1f472edf2a22b89",
+		"value":     1,
+		"timestamp": int64(1600000000000000000) + nonce,
+		"nonce":     nonce,
+		"simulate":  false,
+		"type":      "EVM",
+	}
+}
+
+func TestServer_SendTransaction(t *testing.T) {
+	srv := NewServer(2)
+	defer srv.Close()
+	srv.SetReceiptDelay(1)
+
+	resp := call(t, srv.URL(0), "tx_sendTransaction", testTx(1))
+	assert.Equal(t, 0, resp.Code)
+	var hash string
+	assert.Nil(t, json.Unmarshal(resp.Result, &hash))
+
+	// the same transaction always has the same hash
+	other := NewServer(1)
+	defer other.Close()
+	resp = call(t, other.URL(0), "tx_sendTransaction", testTx(1))
+	assert.Equal(t, `"`+hash+`"`, string(resp.Result))
+
+	resp = call(t, srv.URL(1), "tx_sendTransaction", testTx(1))
+	assert.Equal(t, DuplicateTransactionsCode, resp.Code)
+
+	resp = call(t, srv.URL(1), "tx_getTransactionReceipt", hash)
+	assert.Equal(t, DataNotExistCode, resp.Code)
+	resp = call(t, srv.URL(1), "tx_getTransactionReceipt", hash)
+	assert.Equal(t, 0, resp.Code)
+	var receipt Receipt
+	assert.Nil(t, json.Unmarshal(resp.Result, &receipt))
+	assert.Equal(t, hash, receipt.TxHash)
+	assert.True(t, receipt.Valid)
+
+	resp = call(t, srv.URL(0), "block_getBlockByNumber", "latest", false)
+	assert.Equal(t, 0, resp.Code)
+	assert.Contains(t, string(resp.Result), hash)
+	assert.Equal(t, uint64(1), srv.Height())
+	assert.Equal(t, 2, srv.Requests("tx_sendTransaction"))
+	assert.Equal(t, 1, srv.NodeRequests(1, "tx_sendTransaction"))
+}
+
+func TestServer_Contract(t *testing.T) {
+	srv := NewServer(1)
+	defer srv.Close()
+	srv.HandleContract(func(tx *Tx) (string, error) {
+		return tx.Payload + "01", nil
+	})
+
+	deploy := testTx(2)
+	delete(deploy, "to")
+	delete(deploy, "value")
+	deploy["payload"] = "0x6060"
+	resp := call(t, srv.URL(0), "simulate_deployContract", deploy)
+	var receipt Receipt
+	assert.Nil(t, json.Unmarshal(resp.Result, &receipt))
+	address := receipt.ContractAddress
+	assert.Len(t, address, 42)
+	assert.Equal(t, uint64(0), srv.Height())
+
+	resp = call(t, srv.URL(0), "contract_deployContract", deploy)
+	assert.Equal(t, 0, resp.Code)
+	resp = call(t, srv.URL(0), "contract_getCode", address)
+	assert.Equal(t, `"0x6060"`, string(resp.Result))
+
+	invoke := testTx(3)
+	invoke["to"] = address
+	invoke["payload"] = "0xab"
+	resp = call(t, srv.URL(0), "simulate_invokeContract", invoke)
+	assert.Nil(t, json.Unmarshal(resp.Result, &receipt))
+	assert.Equal(t, "0xab01", receipt.Ret)
+
+	// frozen contract can not be invoked
+	maintain := testTx(4)
+	maintain["to"] = address
+	maintain["opcode"] = 2
+	delete(maintain, "value")
+	call(t, srv.URL(0), "contract_maintainContract", maintain)
+	resp = call(t, srv.URL(0), "simulate_invokeContract", invoke)
+	assert.Equal(t, ContractInvokeErrorCode, resp.Code)
+}
+
+func TestServer_Fault(t *testing.T) {
+	srv := NewServer(2)
+	defer srv.Close()
+
+	srv.InjectFault(0, Fault{Method: "node_getNodes", Times: 1, StatusCode: http.StatusServiceUnavailable})
+	resp, err := http.Post(srv.URL(0), "application/json", strings.NewReader(`{"method":"node_getNodes","id":1}`))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	_ = resp.Body.Close()
+	assert.Equal(t, 0, call(t, srv.URL(0), "node_getNodes").Code)
+
+	srv.InjectFault(AllNodes, Fault{Code: -32006, Message: "system is busy"})
+	for i := 0; i < srv.NodeNum(); i++ {
+		r := call(t, srv.URL(i), "block_getChainHeight")
+		assert.Equal(t, -32006, r.Code)
+		assert.Equal(t, "system is busy", r.Message)
+	}
+	srv.ClearFaults()
+
+	srv.InjectFault(1, Fault{Delay: 100 * time.Millisecond})
+	start := time.Now()
+	assert.Equal(t, 0, call(t, srv.URL(1), "node_getNodeHash").Code)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	srv.ClearFaults()
+	srv.InjectFault(1, Fault{Drop: true})
+	_, err = http.Post(srv.URL(1), "application/json", strings.NewReader(`{"method":"node_getNodes","id":1}`))
+	assert.NotNil(t, err)
+}
+
+func TestServer_Batch(t *testing.T) {
+	srv := NewServer(1)
+	defer srv.Close()
+	call(t, srv.URL(0), "tx_sendTransaction", testTx(5))
+
+	body := `[{"id":1,"method":"block_getChainHeight"},{"id":2,"method":"tx_unknown"}]`
+	resp, err := http.Post(srv.URL(0), "application/json", strings.NewReader(body))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	var resps []*testResponse
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&resps))
+	assert.Len(t, resps, 2)
+	assert.Equal(t, `"0x1"`, string(resps[0].Result))
+	assert.Equal(t, MethodNotExistOrInvalidCode, resps[1].Code)
+}
+
+func TestServer_RequireAuth(t *testing.T) {
+	srv := NewServer(1)
+	defer srv.Close()
+	srv.RequireAuth(true)
+
+	assert.Equal(t, InvalidRequestCode, call(t, srv.URL(0), "node_getNodes").Code)
+	body := `[{"id":1,"method":"node_getNodes","auth":{"timestamp":1,"address":"0x0","signature":"0x01"}},` +
+		`{"id":2,"method":"node_getNodes"}]`
+	resp, err := http.Post(srv.URL(0), "application/json", strings.NewReader(body))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	var resps []*testResponse
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&resps))
+	assert.Len(t, resps, 2)
+	assert.Equal(t, 0, resps[0].Code)
+	assert.Equal(t, InvalidRequestCode, resps[1].Code)
+
+	srv.RequireAuth(false)
+	assert.Equal(t, 0, call(t, srv.URL(0), "node_getNodes").Code)
+}
+
+func TestServer_SubscribeBlock(t *testing.T) {
+	srv := NewServer(1)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+srv.Hosts()[0]+":"+srv.Ports()[0], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	assert.Nil(t, conn.WriteJSON(map[string]interface{}{
+		"jsonrpc": "2.0", "id": 1, "method": "sub_subscribe", "params": []interface{}{"block", false},
+	}))
+	var resp testResponse
+	assert.Nil(t, conn.ReadJSON(&resp))
+	assert.Equal(t, "SUCCESS", resp.Message)
+	var subID string
+	assert.Nil(t, json.Unmarshal(resp.Result, &subID))
+
+	call(t, srv.URL(0), "tx_sendTransaction", testTx(6))
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	assert.Nil(t, conn.ReadJSON(&resp))
+	assert.Equal(t, "", resp.Message)
+	var notification struct {
+		Event        string                 `json:"event"`
+		Subscription string                 `json:"subscription"`
+		Data         map[string]interface{} `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(resp.Result, &notification))
+	assert.Equal(t, "block", notification.Event)
+	assert.Equal(t, subID, notification.Subscription)
+	assert.Equal(t, "0x1", notification.Data["number"])
+}
@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperchain/gosdk/common"
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testFrom = "0x000f1a7a08ccc48e5d30f80850cf1cf283aa3abd"
+
+func newRPCWithServer(t *testing.T, nodeNum int) (*RPC, *rpctest.Server, func()) {
+	srv := rpctest.NewServer(nodeNum)
+	dir, err := srv.ConfDir()
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	rp := NewRPCWithPath(dir)
+	return rp, srv, func() {
+		rp.Close()
+		srv.Close()
+	}
+}
+
+func TestRPCTest_Transaction(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 4)
+	defer closeFn()
+	assert.Equal(t, rpctest.DefaultTxVersion, rp.txVersion)
+	srv.SetReceiptDelay(3)
+
+	receipt, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.Nil(t, err)
+	assert.True(t, receipt.Valid)
+	assert.Equal(t, 4, srv.Requests(TRANSACTION+"getTransactionReceipt"))
+
+	info, err := rp.GetTransactionByHash(receipt.TxHash)
+	assert.Nil(t, err)
+	assert.Equal(t, testFrom, info.From)
+
+	block, err := rp.GetLatestBlock()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), block.Number)
+
+	srv.SetReceiptDelay(0)
+	srv.HandleContract(func(tx *rpctest.Tx) (string, error) {
+		return "0x2a", nil
+	})
+	receipt, err = rp.DeployContract(NewTransaction(testFrom).Deploy("6060"))
+	assert.Nil(t, err)
+	address := receipt.ContractAddress
+	receipt, err = rp.InvokeContract(NewTransaction(testFrom).Invoke(address, common.Hex2Bytes("a9059cbb00000001")))
+	assert.Nil(t, err)
+	assert.Equal(t, "0x2a", receipt.Ret)
+
+	blocks, err := rp.GetBlocks(1, 3, true)
+	assert.Nil(t, err)
+	assert.Len(t, blocks, 3)
+}
+
+func TestRPCTest_Failover(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	rp.Selector(NewRoundRobinSelector())
+	events := rp.NodeEvents(2)
+
+	srv.InjectFault(0, rpctest.Fault{StatusCode: http.StatusServiceUnavailable})
+	// the bad node is chosen by one of the first two calls in turn
+	for i := 0; i < 2; i++ {
+		_, _ = rp.GetNodes()
+	}
+	select {
+	case event := <-events:
+		assert.Equal(t, srv.URL(0), event.URL)
+		assert.False(t, event.IsUp())
+	case <-time.After(2 * time.Second):
+		t.Fatal("bad node is not tripped")
+	}
+
+	// all requests go to the healthy node after the bad one is tripped
+	before := srv.NodeRequests(1, NODE+"getNodes")
+	for i := 0; i < 10; i++ {
+		_, err := rp.GetNodes()
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, before+10, srv.NodeRequests(1, NODE+"getNodes"))
+
+	srv.ClearFaults()
+	select {
+	case event := <-events:
+		assert.Equal(t, srv.URL(0), event.URL)
+		assert.True(t, event.IsUp())
+	case <-time.After(2 * time.Second):
+		t.Fatal("bad node is not reconnected")
+	}
+}
+
+func TestRPCTest_FastInvokeContract(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+
+	urls := rp.NodeURLs()
+	assert.Len(t, urls, 2)
+	for i, tx := range []*Transaction{
+		NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1),
+		NewTransaction(testFrom).Invoke("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", common.Hex2Bytes("a9059cbb00000001")),
+	} {
+		tx.Sign(testKeys(t)["ecdsa"])
+		body, err := rp.TxRequestBody(tx)
+		assert.Nil(t, err)
+		stat, err := rp.FastInvokeContract(body, urls[i])
+		assert.Nil(t, err)
+		assert.False(t, stat.ResponseTime.Before(stat.RequestTime))
+		assert.Contains(t, string(stat.TxReceipt), tx.GetHash())
+	}
+	assert.Equal(t, 1, srv.NodeRequests(0, TRANSACTION+"sendTransaction"))
+	assert.Equal(t, 1, srv.NodeRequests(1, CONTRACT+"invokeContract"))
+}
+
+func TestRPCTest_FastInvokeContractInspector(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+	srv.RequireAuth(true)
+	rp.im.enable = true
+	rp.SetAccount(testKeys(t)["sm2"])
+
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	tx.Sign(testKeys(t)["ecdsa"])
+	body, err := rp.TxRequestBody(tx)
+	assert.Nil(t, err)
+	var req JSONRequest
+	assert.Nil(t, json.Unmarshal(body, &req))
+	assert.NotNil(t, req.Auth)
+
+	stat, err := rp.FastInvokeContract(body, rp.NodeURLs()[0])
+	assert.Nil(t, err)
+	var resp JSONResponse
+	assert.Nil(t, json.Unmarshal(stat.TxReceipt, &resp))
+	assert.Equal(t, SuccessCode, resp.Code, resp.Message)
+	assert.Contains(t, string(resp.Result), tx.GetHash())
+}
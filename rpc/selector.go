@@ -0,0 +1,273 @@
+package rpc
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperchain/gosdk/common"
+)
+
+// node selector names used in hpc.toml `jsonRPC.selector`
+const (
+	RandomSelector       = "random"
+	RoundRobinSelector   = "roundRobin"
+	WeightedSelector     = "weighted"
+	LeastLatencySelector = "leastLatency"
+	StickySelector       = "sticky"
+
+	// DefaultEWMAAlpha is the smoothing factor of least-latency selector
+	DefaultEWMAAlpha = 0.3
+	// failed requests are recorded as a sample of at least leastLatencyPenalty
+	leastLatencyPenalty = time.Second
+)
+
+// NodeSelector decides which node a request is sent to.
+// Implementations must be safe for concurrent use.
+type NodeSelector interface {
+	// Select returns the index of the chosen node in nodes, or -1 if no node is available.
+	// key is the routing key of the request (the from address of a transaction), it may be empty.
+	Select(nodes []*Node, key string) int
+	// Observe reports the response time of a request sent to node, err is not nil if the request failed.
+	Observe(node *Node, rt time.Duration, err StdError)
+}
+
+// newNodeSelector return the built-in selector by name, random selector is used for unknown name
+func newNodeSelector(name string) NodeSelector {
+	switch name {
+	case "", RandomSelector:
+		return NewRandomSelector()
+	case RoundRobinSelector:
+		return NewRoundRobinSelector()
+	case WeightedSelector:
+		return NewWeightedSelector()
+	case LeastLatencySelector:
+		return NewLeastLatencySelector(DefaultEWMAAlpha)
+	case StickySelector:
+		return NewStickySelector(NewRoundRobinSelector())
+	default:
+		logger.Errorf("unsupport node selector:%s, use %s instead", name, RandomSelector)
+		return NewRandomSelector()
+	}
+}
+
+/*---------------------------------- random ----------------------------------*/
+
+type randomSelector struct{}
+
+// NewRandomSelector return a selector choosing a random available node, it is the default selector
+func NewRandomSelector() NodeSelector {
+	return randomSelector{}
+}
+
+func (randomSelector) Select(nodes []*Node, key string) int {
+	nodeNum := len(nodes)
+	if nodeNum == 0 {
+		return -1
+	}
+	for i := 0; i < nodeNum*2; i++ {
+		index := common.RandInt(nodeNum)
+		if nodes[index].isAvailable() {
+			return index
+		}
+	}
+	//if random fail, try round
+	start := common.RandInt(nodeNum)
+	for i := 0; i < nodeNum; i++ {
+		index := (start + i) % nodeNum
+		if nodes[index].isAvailable() {
+			return index
+		}
+	}
+	return -1
+}
+
+func (randomSelector) Observe(node *Node, rt time.Duration, err StdError) {}
+
+/*---------------------------------- round robin ----------------------------------*/
+
+type roundRobinSelector struct {
+	next uint64
+}
+
+// NewRoundRobinSelector return a selector choosing available nodes in turn
+func NewRoundRobinSelector() NodeSelector {
+	return &roundRobinSelector{}
+}
+
+func (rr *roundRobinSelector) Select(nodes []*Node, key string) int {
+	nodeNum := uint64(len(nodes))
+	if nodeNum == 0 {
+		return -1
+	}
+	start := atomic.AddUint64(&rr.next, 1) - 1
+	for i := uint64(0); i < nodeNum; i++ {
+		index := int((start + i) % nodeNum)
+		if nodes[index].isAvailable() {
+			return index
+		}
+	}
+	return -1
+}
+
+func (rr *roundRobinSelector) Observe(node *Node, rt time.Duration, err StdError) {}
+
+/*---------------------------------- weighted ----------------------------------*/
+
+type weightedSelector struct {
+	mutex   sync.Mutex
+	current map[*Node]int
+}
+
+// NewWeightedSelector return a smooth weighted round-robin selector,
+// the weight of node is set by hpc.toml `jsonRPC.weights` or RPC.NodeWeights
+func NewWeightedSelector() NodeSelector {
+	return &weightedSelector{
+		current: make(map[*Node]int),
+	}
+}
+
+func (ws *weightedSelector) Select(nodes []*Node, key string) int {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	best, total := -1, 0
+	for i, node := range nodes {
+		if !node.isAvailable() {
+			continue
+		}
+		weight := node.getWeight()
+		ws.current[node] += weight
+		total += weight
+		if best == -1 || ws.current[node] > ws.current[nodes[best]] {
+			best = i
+		}
+	}
+	if best != -1 {
+		ws.current[nodes[best]] -= total
+	}
+	return best
+}
+
+func (ws *weightedSelector) Observe(node *Node, rt time.Duration, err StdError) {}
+
+/*---------------------------------- least latency ----------------------------------*/
+
+type leastLatencySelector struct {
+	alpha   float64
+	rwMutex sync.RWMutex
+	ewma    map[*Node]float64
+}
+
+// NewLeastLatencySelector return a selector choosing the available node with the lowest
+// exponentially weighted moving average of response time, alpha is the weight of the latest sample.
+// Nodes never observed are preferred, so every node is measured at least once.
+func NewLeastLatencySelector(alpha float64) NodeSelector {
+	if alpha <= 0 || alpha > 1 {
+		alpha = DefaultEWMAAlpha
+	}
+	return &leastLatencySelector{
+		alpha: alpha,
+		ewma:  make(map[*Node]float64),
+	}
+}
+
+func (ls *leastLatencySelector) Select(nodes []*Node, key string) int {
+	nodeNum := len(nodes)
+	if nodeNum == 0 {
+		return -1
+	}
+	ls.rwMutex.RLock()
+	defer ls.rwMutex.RUnlock()
+
+	// start from a random node so that nodes with equal latency share the load
+	start := common.RandInt(nodeNum)
+	best, bestLatency := -1, float64(0)
+	for i := 0; i < nodeNum; i++ {
+		index := (start + i) % nodeNum
+		if !nodes[index].isAvailable() {
+			continue
+		}
+		latency := ls.ewma[nodes[index]]
+		if best == -1 || latency < bestLatency {
+			best, bestLatency = index, latency
+		}
+	}
+	return best
+}
+
+func (ls *leastLatencySelector) Observe(node *Node, rt time.Duration, err StdError) {
+	sample := float64(rt)
+	if err != nil && rt < leastLatencyPenalty {
+		sample = float64(leastLatencyPenalty)
+	}
+	ls.rwMutex.Lock()
+	defer ls.rwMutex.Unlock()
+	if old, ok := ls.ewma[node]; ok {
+		ls.ewma[node] = ls.alpha*sample + (1-ls.alpha)*old
+	} else {
+		ls.ewma[node] = sample
+	}
+}
+
+// Latency return the current moving average of response time of node
+func (ls *leastLatencySelector) Latency(node *Node) time.Duration {
+	ls.rwMutex.RLock()
+	defer ls.rwMutex.RUnlock()
+	return time.Duration(ls.ewma[node])
+}
+
+/*---------------------------------- sticky ----------------------------------*/
+
+type stickySelector struct {
+	fallback NodeSelector
+}
+
+// NewStickySelector return a selector which always sends requests with the same key (the account address)
+// to the same node as long as it is available, requests without key are delegated to fallback.
+// Nodes are chosen by rendezvous hashing, so only the accounts of a bad node are moved to other nodes.
+func NewStickySelector(fallback NodeSelector) NodeSelector {
+	if fallback == nil {
+		fallback = NewRoundRobinSelector()
+	}
+	return &stickySelector{
+		fallback: fallback,
+	}
+}
+
+func (ss *stickySelector) Select(nodes []*Node, key string) int {
+	if key == "" {
+		return ss.fallback.Select(nodes, key)
+	}
+	best, bestScore := -1, uint64(0)
+	for i, node := range nodes {
+		if !node.isAvailable() {
+			continue
+		}
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		_, _ = h.Write([]byte(node.url))
+		if score := h.Sum64(); best == -1 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+func (ss *stickySelector) Observe(node *Node, rt time.Duration, err StdError) {
+	ss.fallback.Observe(node, rt, err)
+}
+
+// routeKey return the from address of the transaction carried by req, which is used by sticky selector
+func routeKey(req *JSONRequest) string {
+	if len(req.Params) == 0 {
+		return ""
+	}
+	if param, ok := req.Params[0].(map[string]interface{}); ok {
+		if from, ok := param["from"].(string); ok {
+			return from
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package rpc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestNodes(num int) []*Node {
+	nodes := make([]*Node, num)
+	for i := range nodes {
+		nodes[i] = NewNode("localhost", string(rune('1'+i))+"000", "")
+	}
+	return nodes
+}
+
+func TestRandomSelector(t *testing.T) {
+	nodes := newTestNodes(3)
+	selector := NewRandomSelector()
+	nodes[0].setAvailable(false)
+	nodes[2].setAvailable(false)
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, 1, selector.Select(nodes, ""))
+	}
+	nodes[1].setAvailable(false)
+	assert.Equal(t, -1, selector.Select(nodes, ""))
+	assert.Equal(t, -1, selector.Select(nil, ""))
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	nodes := newTestNodes(3)
+	selector := NewRoundRobinSelector()
+	for i := 0; i < 6; i++ {
+		assert.Equal(t, i%3, selector.Select(nodes, ""))
+	}
+	nodes[1].setAvailable(false)
+	var got []int
+	for i := 0; i < 4; i++ {
+		got = append(got, selector.Select(nodes, ""))
+	}
+	assert.Equal(t, []int{0, 2, 2, 0}, got)
+}
+
+func TestWeightedSelector(t *testing.T) {
+	nodes := newTestNodes(3)
+	nodes[0].setWeight(5)
+	selector := NewWeightedSelector()
+	count := make(map[int]int)
+	for i := 0; i < 70; i++ {
+		count[selector.Select(nodes, "")]++
+	}
+	assert.Equal(t, 50, count[0])
+	assert.Equal(t, 10, count[1])
+	assert.Equal(t, 10, count[2])
+
+	// smooth: the heavy node is not chosen 5 times in a row
+	var seq []int
+	for i := 0; i < 7; i++ {
+		seq = append(seq, selector.Select(nodes, ""))
+	}
+	assert.Equal(t, []int{0, 0, 1, 0, 2, 0, 0}, seq)
+}
+
+func TestLeastLatencySelector(t *testing.T) {
+	nodes := newTestNodes(3)
+	selector := NewLeastLatencySelector(0.5)
+	selector.Observe(nodes[0], 30*time.Millisecond, nil)
+	selector.Observe(nodes[1], 10*time.Millisecond, nil)
+	selector.Observe(nodes[2], 20*time.Millisecond, nil)
+	assert.Equal(t, 1, selector.Select(nodes, ""))
+
+	// failure is penalized
+	selector.Observe(nodes[1], time.Millisecond, NewGetResponseError(assert.AnError))
+	assert.Equal(t, 2, selector.Select(nodes, ""))
+
+	ls := selector.(*leastLatencySelector)
+	assert.Equal(t, 20*time.Millisecond, ls.Latency(nodes[2]))
+
+	nodes[2].setAvailable(false)
+	assert.Equal(t, 0, selector.Select(nodes, ""))
+
+	// node never observed is preferred
+	nodes = append(nodes, NewNode("localhost", "4000", ""))
+	assert.Equal(t, 3, selector.Select(nodes, ""))
+}
+
+func TestStickySelector(t *testing.T) {
+	nodes := newTestNodes(4)
+	selector := NewStickySelector(nil)
+	accounts := []string{"0x1111", "0x2222", "0x3333", "0x4444", "0x5555"}
+	chosen := make(map[string]int)
+	for _, a := range accounts {
+		chosen[a] = selector.Select(nodes, a)
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, chosen[a], selector.Select(nodes, a))
+		}
+	}
+
+	bad := chosen[accounts[0]]
+	nodes[bad].setAvailable(false)
+	assert.NotEqual(t, bad, selector.Select(nodes, accounts[0]))
+	for _, a := range accounts {
+		if chosen[a] != bad {
+			assert.Equal(t, chosen[a], selector.Select(nodes, a))
+		}
+	}
+
+	// requests without account use round robin
+	assert.NotEqual(t, selector.Select(nodes, ""), selector.Select(nodes, ""))
+}
+
+func TestSelectorConcurrent(t *testing.T) {
+	nodes := newTestNodes(4)
+	selectors := []NodeSelector{
+		NewRandomSelector(),
+		NewRoundRobinSelector(),
+		NewWeightedSelector(),
+		NewLeastLatencySelector(DefaultEWMAAlpha),
+		NewStickySelector(nil),
+	}
+	for _, selector := range selectors {
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					index := selector.Select(nodes, "0xabcd")
+					assert.True(t, index >= 0 && index < len(nodes))
+					selector.Observe(nodes[index], time.Duration(g)*time.Millisecond, nil)
+					nodes[0].setAvailable(i%2 == 0)
+				}
+			}(g)
+		}
+		wg.Wait()
+		for _, node := range nodes {
+			node.setAvailable(true)
+		}
+	}
+}
+
+func TestRouteKey(t *testing.T) {
+	tx := NewTransaction("0xfc546753921c1d1bc2d444c5186a73ab5802a0b4").Transfer("0x1", 1)
+	rp := DefaultRPC()
+	assert.Equal(t, tx.from, routeKey(rp.jsonRPC("tx_sendTransaction", tx.Serialize())))
+	assert.Equal(t, "", routeKey(rp.jsonRPC("tx_getTransactionReceipt", "0x1234")))
+	assert.Equal(t, "", routeKey(rp.jsonRPC("node_getNodes")))
+}
@@ -0,0 +1,210 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultSubmitConcurrency is the default number of workers of a Submitter
+	DefaultSubmitConcurrency = 16
+	// DefaultSubmitQueueSize is the default number of transactions waiting for a worker
+	DefaultSubmitQueueSize = 1024
+)
+
+// SubmitterConfig is the config of Submitter, zero values are replaced by defaults
+type SubmitterConfig struct {
+	// Concurrency is the number of transactions sent and polled at the same time
+	Concurrency int
+	// QueueSize is the number of submitted transactions waiting for a worker,
+	// Submit blocks and TrySubmit fails with ErrQueueFull when the queue is full
+	QueueSize int
+}
+
+// Submitter sends transactions asynchronously through a bounded pool of workers,
+// every worker sends a transaction and polls its receipt like SendTx does.
+type Submitter struct {
+	rpc     *RPC
+	queue   chan *submission
+	closing chan struct{}
+	wg      sync.WaitGroup
+	pending int64
+
+	rwMutex   sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+}
+
+type submission struct {
+	ctx    context.Context
+	tx     *Transaction
+	future *Future
+}
+
+// Future is the result of a submitted transaction, which is resolved when
+// the receipt is got or the sending fails
+type Future struct {
+	hash    string
+	done    chan struct{}
+	receipt *TxReceipt
+	err     StdError
+}
+
+// Hash return the hash of the transaction computed locally when it is submitted
+func (f *Future) Hash() string {
+	return f.hash
+}
+
+// Done return a channel which is closed when the future is resolved
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get wait until the future is resolved, then return the receipt or the error
+func (f *Future) Get() (*TxReceipt, StdError) {
+	<-f.done
+	return f.receipt, f.err
+}
+
+// Wait is the same as Get, but return early if ctx is done, the transaction keeps going in that case
+func (f *Future) Wait(ctx context.Context) (*TxReceipt, StdError) {
+	select {
+	case <-f.done:
+		return f.receipt, f.err
+	case <-ctx.Done():
+		return nil, NewRequestCanceledError(ctx.Err())
+	}
+}
+
+func (f *Future) resolve(receipt *TxReceipt, err StdError) {
+	f.receipt, f.err = receipt, err
+	close(f.done)
+}
+
+// NewSubmitter return a Submitter sending transactions by rpc, Close should be called when it is not used anymore
+func (rpc *RPC) NewSubmitter(config SubmitterConfig) *Submitter {
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultSubmitConcurrency
+	}
+	if config.QueueSize < 0 {
+		config.QueueSize = 0
+	} else if config.QueueSize == 0 {
+		config.QueueSize = DefaultSubmitQueueSize
+	}
+	s := &Submitter{
+		rpc:     rpc,
+		queue:   make(chan *submission, config.QueueSize),
+		closing: make(chan struct{}),
+	}
+	s.wg.Add(config.Concurrency)
+	for i := 0; i < config.Concurrency; i++ {
+		go s.work()
+	}
+	return s
+}
+
+// Submit queues a signed transaction, it blocks while the queue is full until ctx is done.
+// The transaction is sent as SendTx, DeployContract, InvokeContract or MaintainContract does
+// according to how it is built, and ctx is bound to the sending and the receipt polling.
+func (s *Submitter) Submit(ctx context.Context, transaction *Transaction) (*Future, StdError) {
+	return s.submit(ctx, transaction, true)
+}
+
+// TrySubmit is the same as Submit, but it fails with ErrQueueFull at once if the queue is full
+func (s *Submitter) TrySubmit(ctx context.Context, transaction *Transaction) (*Future, StdError) {
+	return s.submit(ctx, transaction, false)
+}
+
+func (s *Submitter) submit(ctx context.Context, transaction *Transaction, block bool) (*Future, StdError) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	// SendTx sends the transaction with the tx version of rpc, which must be set before it is hashed
+	if !transaction.isDeploy && !transaction.isMaintain && !transaction.isInvoke {
+		transaction.txVersion = s.rpc.txVersion
+	}
+	sub := &submission{
+		ctx: ctx,
+		tx:  transaction,
+		future: &Future{
+			hash: transaction.GetHash(),
+			done: make(chan struct{}),
+		},
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	if s.closed {
+		return nil, newSubmitError(ErrSubmitterClosed)
+	}
+	atomic.AddInt64(&s.pending, 1)
+	if !block {
+		select {
+		case s.queue <- sub:
+			return sub.future, nil
+		default:
+			atomic.AddInt64(&s.pending, -1)
+			return nil, newSubmitError(ErrQueueFull)
+		}
+	}
+	select {
+	case s.queue <- sub:
+		return sub.future, nil
+	case <-ctx.Done():
+		atomic.AddInt64(&s.pending, -1)
+		return nil, NewRequestCanceledError(ctx.Err())
+	case <-s.closing:
+		atomic.AddInt64(&s.pending, -1)
+		return nil, newSubmitError(ErrSubmitterClosed)
+	}
+}
+
+// Pending return the number of transactions submitted but not resolved yet
+func (s *Submitter) Pending() int {
+	return int(atomic.LoadInt64(&s.pending))
+}
+
+// Close stops accepting transactions and waits until the queued ones are resolved
+func (s *Submitter) Close() {
+	s.closeOnce.Do(func() {
+		// wake up the blocked submitters first, so the lock can be held
+		close(s.closing)
+		s.rwMutex.Lock()
+		s.closed = true
+		close(s.queue)
+		s.rwMutex.Unlock()
+	})
+	s.wg.Wait()
+}
+
+func (s *Submitter) work() {
+	defer s.wg.Done()
+	for sub := range s.queue {
+		var (
+			receipt *TxReceipt
+			err     StdError
+		)
+		if sub.ctx.Err() != nil {
+			err = NewRequestCanceledError(sub.ctx.Err())
+		} else {
+			receipt, err = s.rpc.WithContext(sub.ctx).sendTransaction(sub.tx)
+		}
+		atomic.AddInt64(&s.pending, -1)
+		sub.future.resolve(receipt, err)
+	}
+}
+
+// sendTransaction sends the transaction by the api matching how it is built
+func (rpc *RPC) sendTransaction(transaction *Transaction) (*TxReceipt, StdError) {
+	switch {
+	case transaction.isDeploy:
+		return rpc.DeployContract(transaction)
+	case transaction.isMaintain:
+		return rpc.MaintainContract(transaction)
+	case transaction.isInvoke:
+		return rpc.InvokeContract(transaction)
+	default:
+		return rpc.SendTx(transaction)
+	}
+}
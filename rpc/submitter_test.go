@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmitter_Submit(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+	srv.SetReceiptDelay(1)
+	s := rp.NewSubmitter(SubmitterConfig{Concurrency: 4})
+	defer s.Close()
+
+	var futures []*Future
+	for i := 0; i < 20; i++ {
+		future, err := s.Submit(context.Background(), NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", int64(i+1)))
+		assert.Nil(t, err)
+		futures = append(futures, future)
+	}
+	future, err := s.Submit(context.Background(), NewTransaction(testFrom).Deploy("6060"))
+	assert.Nil(t, err)
+	futures = append(futures, future)
+
+	for _, future := range futures {
+		receipt, err := future.Get()
+		assert.Nil(t, err)
+		assert.True(t, receipt.Valid)
+		assert.Equal(t, future.Hash(), receipt.TxHash)
+	}
+	assert.NotEmpty(t, futures[20].receipt.ContractAddress)
+	assert.Equal(t, 0, s.Pending())
+	assert.Equal(t, 20, srv.Requests(TRANSACTION+"sendTransaction"))
+	assert.Equal(t, 1, srv.Requests(CONTRACT+"deployContract"))
+}
+
+func TestSubmitter_TxVersion(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	rp.txVersion = "1.0"
+	srv.SetTxHasher(hashLikeClient(rp))
+	s := rp.NewSubmitter(SubmitterConfig{Concurrency: 1})
+	defer s.Close()
+
+	// the hash is computed with the tx version SendTx sends the transaction with
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	tx.txVersion = "2.5"
+	future, err := s.Submit(context.Background(), tx)
+	assert.Nil(t, err)
+	receipt, err := future.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, future.Hash(), receipt.TxHash)
+	assert.Equal(t, tx.GetHash(), future.Hash())
+}
+
+func TestSubmitter_Backpressure(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{Method: TRANSACTION + "sendTransaction", Delay: 100 * time.Millisecond})
+	s := rp.NewSubmitter(SubmitterConfig{Concurrency: 1, QueueSize: 1})
+
+	first, err := s.Submit(context.Background(), NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.Nil(t, err)
+	// wait until the worker takes the first one
+	assert.Eventually(t, func() bool {
+		return len(s.queue) == 0
+	}, time.Second, time.Millisecond)
+	second, err := s.Submit(context.Background(), NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 2))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.Pending())
+
+	_, err = s.TrySubmit(context.Background(), NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 3))
+	assert.True(t, errors.Is(err, ErrQueueFull))
+	assert.True(t, IsRetryable(err))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = s.Submit(ctx, NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 3))
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.Equal(t, 2, s.Pending())
+
+	// waiting on a future can be given up
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = second.Wait(ctx)
+	assert.True(t, errors.Is(err, ErrTimeout))
+
+	// queued transactions are resolved before close returns
+	s.Close()
+	for _, future := range []*Future{first, second} {
+		select {
+		case <-future.Done():
+		default:
+			t.Fatal("future is not resolved")
+		}
+		receipt, err := future.Get()
+		assert.Nil(t, err)
+		assert.True(t, receipt.Valid)
+	}
+	_, err = s.Submit(context.Background(), NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 4))
+	assert.True(t, errors.Is(err, ErrSubmitterClosed))
+	assert.Equal(t, 0, s.Pending())
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// tags set on the spans of rpc
+const (
+	TagMethod    = "gosdk.method"
+	TagNodeURL   = "gosdk.node"
+	TagTxHash    = "gosdk.tx_hash"
+	TagRetry     = "gosdk.retry"
+	TagPolls     = "gosdk.polls"
+	TagErrorCode = "gosdk.error_code"
+
+	component = "gosdk"
+)
+
+// Tracer set the tracer used to record spans of calls, receipt polling, websocket subscription
+// and file transfer, no span is recorded if it is not set. A span is the child of the span in
+// the context bound by WithContext, and its context is sent to node in http headers.
+func (rpc *RPC) Tracer(tracer opentracing.Tracer) *RPC {
+	rpc.hrm.tracer = tracer
+	return rpc
+}
+
+// startSpan starts a span of operation as child of the span in the context of rpc,
+// and return the rpc bound to the context carrying the new span
+func (rpc *RPC) startSpan(operation string) (opentracing.Span, *RPC) {
+	if rpc.hrm.tracer == nil {
+		return noopSpan, rpc
+	}
+	span, ctx := rpc.hrm.startSpan(rpc.getContext(), operation)
+	return span, rpc.WithContext(ctx)
+}
+
+var noopSpan = opentracing.NoopTracer{}.StartSpan("")
+
+// startSpan starts a span of operation as child of the span in ctx,
+// the returned context carries the new span
+func (hrm *httpRequestManager) startSpan(ctx context.Context, operation string) (opentracing.Span, context.Context) {
+	if hrm.tracer == nil {
+		return noopSpan, ctx
+	}
+	var opts []opentracing.StartSpanOption
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+	span := hrm.tracer.StartSpan(operation, opts...)
+	ext.Component.Set(span, component)
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+// finishSpan marks span as failed if err is not nil, then finishes it
+func finishSpan(span opentracing.Span, err StdError) {
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.SetTag(TagErrorCode, err.Code())
+		span.LogFields(log.String("message", err.Error()))
+	}
+	span.Finish()
+}
+
+// tracingInterceptor tags the node of every attempt on the span in ctx
+// and sends the span context to node in http headers
+func (hrm *httpRequestManager) tracingInterceptor(ctx context.Context, call *Call, next Handler) StdError {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return next(ctx, call)
+	}
+	span.SetTag(TagNodeURL, call.URL)
+	if call.Header != nil {
+		carrier := opentracing.HTTPHeadersCarrier(call.Header)
+		if err := span.Tracer().Inject(span.Context(), opentracing.HTTPHeaders, carrier); err != nil {
+			logger.Warningf("inject span context error: %v", err)
+		}
+	}
+
+	err := next(ctx, call)
+	fields := []log.Field{log.String("event", "request"), log.String("node", call.URL)}
+	if err != nil {
+		fields = append(fields, log.Int("code", err.Code()))
+	}
+	span.LogFields(fields...)
+	return err
+}
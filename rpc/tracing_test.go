@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/hyperchain/gosdk/rpc/rpctest"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+)
+
+func finishedSpans(tracer *mocktracer.MockTracer, operation string) []*mocktracer.MockSpan {
+	var spans []*mocktracer.MockSpan
+	for _, span := range tracer.FinishedSpans() {
+		if span.OperationName == operation {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestTracer_Call(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	tracer := mocktracer.New()
+	rp.Tracer(tracer)
+
+	parent := tracer.StartSpan("parent")
+	ctx := opentracing.ContextWithSpan(context.Background(), parent)
+	_, err := rp.WithContext(ctx).GetNodes()
+	assert.Nil(t, err)
+	_, err = rp.GetBlockByNumber(100, true)
+	assert.NotNil(t, err)
+
+	spans := finishedSpans(tracer, NODE+"getNodes")
+	assert.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, parent.Context().(mocktracer.MockSpanContext).SpanID, span.ParentID)
+	assert.Equal(t, NODE+"getNodes", span.Tag(TagMethod))
+	assert.Equal(t, rp.hrm.nodes[0].url, span.Tag(TagNodeURL))
+	assert.Nil(t, span.Tag(TagErrorCode))
+
+	// span context is sent in http headers
+	header := srv.LastHeader(NODE + "getNodes")
+	assert.Equal(t, strconv.Itoa(span.SpanContext.SpanID), header.Get("Mockpfx-Ids-Spanid"))
+	assert.Equal(t, strconv.Itoa(span.SpanContext.TraceID), header.Get("Mockpfx-Ids-Traceid"))
+
+	spans = finishedSpans(tracer, BLOCK+"getBlockByNumber")
+	assert.Len(t, spans, 1)
+	assert.Equal(t, 0, spans[0].ParentID)
+	assert.Equal(t, err.Code(), spans[0].Tag(TagErrorCode))
+	assert.Equal(t, true, spans[0].Tag("error"))
+}
+
+func TestTracer_CallByPolling(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	tracer := mocktracer.New()
+	rp.Tracer(tracer)
+	srv.SetReceiptDelay(2)
+	srv.InjectFault(rpctest.AllNodes, rpctest.Fault{Method: TRANSACTION + "sendTransaction", Times: 1, StatusCode: http.StatusServiceUnavailable})
+	// retry on another node once
+	rp.Use(func(ctx context.Context, call *Call, next Handler) StdError {
+		if err := next(ctx, call); err == nil || err.Code() != GetResponseErrorCode {
+			return err
+		}
+		call.URL = ""
+		return next(ctx, call)
+	})
+
+	receipt, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.Nil(t, err)
+
+	polling := finishedSpans(tracer, "CallByPolling")
+	assert.Len(t, polling, 1)
+	assert.Equal(t, TRANSACTION+"sendTransaction", polling[0].Tag(TagMethod))
+	assert.Equal(t, receipt.TxHash, polling[0].Tag(TagTxHash))
+	assert.Equal(t, int64(0), polling[0].Tag(TagRetry))
+
+	send := finishedSpans(tracer, TRANSACTION+"sendTransaction")
+	assert.Len(t, send, 1)
+	assert.Equal(t, polling[0].SpanContext.SpanID, send[0].ParentID)
+	// the failed attempt and the successful one are logged
+	assert.Len(t, send[0].Logs(), 2)
+
+	receiptPolling := finishedSpans(tracer, "GetTxReceiptByPolling")
+	assert.Len(t, receiptPolling, 1)
+	assert.Equal(t, polling[0].SpanContext.SpanID, receiptPolling[0].ParentID)
+	assert.Equal(t, 3, receiptPolling[0].Tag(TagPolls))
+	for _, span := range finishedSpans(tracer, TRANSACTION+"getTransactionReceipt") {
+		assert.Equal(t, receiptPolling[0].SpanContext.SpanID, span.ParentID)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"strconv"
 	"strings"
 	"time"
 
@@ -97,35 +96,6 @@ type Transaction struct {
 	isPrivateTx  bool
 }
 
-func GetTxVersionInt(txVersionStr string) TxVersionInt {
-	switch txVersionStr {
-	case "1.0":
-		return TxVersion10
-	case "2.0":
-		return TxVersion20
-	case "2.1":
-		return TxVersion21
-	case "2.2":
-		return TxVersion22
-	case "2.3":
-		return TxVersion23
-	case "2.4":
-		return TxVersion24
-	case "2.5":
-		return TxVersion25
-	case "2.6":
-		return TxVersion26
-	case "2.7":
-		return TxVersion27
-	case "2.8":
-		return TxVersion28
-	case "2.9":
-		return TxVersion29
-	default:
-		return TxVersion10
-	}
-}
-
 // NewTransaction return a empty transaction
 func NewTransaction(from string) *Transaction {
 	return &Transaction{
@@ -399,158 +369,27 @@ func (t *Transaction) getTxVersion() string {
 	return t.txVersion
 }
 
-// needHashString construct a stirng that need to hash
-func needHashString(t *Transaction) string {
-	p := getProcessor(t.txVersion)
-	sb := &strings.Builder{}
-	p.process(sb, t)
-	return sb.String()
-}
-
-type processor interface {
-	process(buffer *strings.Builder, t *Transaction)
-}
-
-type processorWithHyperchain struct{}
-
-func newProcessorWithHyperchain() *processorWithHyperchain {
-	return &processorWithHyperchain{}
-}
-
-func (p *processorWithHyperchain) process(buffer *strings.Builder, t *Transaction) {
-	var payload string
-	if t.isValue {
-		payload = "0x" + strconv.FormatInt(t.value, 16)
-	} else if (t.isMaintain && t.opcode != 1) || t.payload == "" {
-		payload = "0x0"
-	} else {
-		payload = strings.ToLower(common.StringToHex(t.payload))
-	}
-
-	writeBaseFiled(buffer, t, payload, false)
-}
-
-type processorWithFlato struct{}
-
-func newProcessorWithFlato() *processorWithFlato {
-	return &processorWithFlato{}
-}
-
-func (p *processorWithFlato) process(buffer *strings.Builder, t *Transaction) {
-	var payload string
-	if (t.isMaintain && t.opcode != 1) || t.payload == "" {
-		payload = "0x0"
-	} else {
-		payload = strings.ToLower(common.StringToHex(t.payload))
-	}
-
-	writeBaseFiled(buffer, t, payload, true)
-	buffer.WriteString("&version=")
-	buffer.WriteString(t.txVersion)
-}
-
-type processorWithFlato21 struct {
-	flato *processorWithFlato
-}
-
-func newProcessorWithFlato21() *processorWithFlato21 {
-	return &processorWithFlato21{
-		flato: newProcessorWithFlato(),
-	}
-}
-
-func (p *processorWithFlato21) process(buffer *strings.Builder, t *Transaction) {
-	p.flato.process(buffer, t)
-	strExtraId, err := t.GetExtraIdString()
-	if err != nil {
-		logger.Warning("GetExtraIdString failed: " + err.Error())
-	}
-	buffer.WriteString("&extraid=")
-	buffer.WriteString(strExtraId)
-}
-
-type processorWithFlato22 struct {
-	flato21 *processorWithFlato21
-}
-
-func newProcessorWithFlato22() *processorWithFlato22 {
-	return &processorWithFlato22{
-		flato21: newProcessorWithFlato21(),
-	}
-}
-
-func (p *processorWithFlato22) process(buffer *strings.Builder, t *Transaction) {
-	p.flato21.process(buffer, t)
-	buffer.WriteString("&cname=")
-	buffer.WriteString(t.cName)
-}
-
-func getProcessor(txVersion string) processor {
-	if txVersion < "2" {
-		return newProcessorWithHyperchain()
-	}
-	if txVersion < "2.1" {
-		return newProcessorWithFlato()
-	}
-	if txVersion < "2.2" {
-		return newProcessorWithFlato21()
-	}
-	return newProcessorWithFlato22()
-}
-
-func writeBaseFiled(buffer *strings.Builder, t *Transaction, payload string, hasPayload bool) {
-	buffer.WriteString("from=")
-	buffer.WriteString(common.StringToHex(strings.ToLower(t.from)))
-	buffer.WriteString("&to=")
-	buffer.WriteString(common.StringToHex(strings.ToLower(t.to)))
-	if hasPayload {
-		buffer.WriteString("&value=0x")
-		buffer.WriteString(strconv.FormatInt(t.value, 16))
-	}
-	if hasPayload {
-		buffer.WriteString("&payload=")
-	} else {
-		buffer.WriteString("&value=")
-	}
-	buffer.WriteString(payload)
-	buffer.WriteString("&timestamp=0x")
-	buffer.WriteString(strconv.FormatInt(t.timestamp, 16))
-	buffer.WriteString("&nonce=0x")
-	buffer.WriteString(strconv.FormatInt(t.nonce, 16))
-	buffer.WriteString("&opcode=")
-	buffer.WriteString(strconv.FormatInt(t.opcode, 16))
-	buffer.WriteString("&extra=")
-	buffer.WriteString(t.extra)
-	buffer.WriteString("&vmtype=")
-	buffer.WriteString(t.vmType)
-}
-
-// Sign support ecdsa\SM2\Ed25519 signature
-func (t *Transaction) Sign(key interface{}) {
+// Sign support ecdsa\SM2\Ed25519 signature, key is an account.Key or account.Signer
+func (t *Transaction) Sign(key account.Signer) {
 	t.sign(key, false)
 }
 
 // SignWIthBatch support ecdsa\SM2\Ed25519 signature
 // Only affect sm2 signature, other types (ED25519/ECDSA) are the same as Sign
 // Only flato 1.0.2 +
-func (t *Transaction) SignWithBatchFlag(key interface{}) {
+func (t *Transaction) SignWithBatchFlag(key account.Signer) {
 	t.sign(key, true)
 }
 
-func (t *Transaction) sign(key interface{}, batch bool) {
-	if t.isPrivateTx {
-		K, ok := key.(account.Key)
-		if !ok {
-			logger.Error("invalid key type")
-			return
-		}
-		t.PreSign(K)
+func (t *Transaction) sign(key account.Signer, batch bool) {
+	if key == nil {
+		logger.Error("invalid key type")
+		return
 	}
-	_, isPKIAccount := key.(*account.PKIKey)
-	if isPKIAccount {
-		key = key.(*account.PKIKey).GetNormalKey()
+	if t.isPrivateTx {
+		t.preSign(key)
 	}
-	sig, err := sign(key, needHashString(t), batch, isPKIAccount)
+	sig, err := sign(key, needHashString(t), batch)
 	if err != nil {
 		logger.Error("ecdsa signature error")
 		return
@@ -558,7 +397,7 @@ func (t *Transaction) sign(key interface{}, batch bool) {
 	t.signature = sig
 }
 
-func (t *Transaction) SignWithClang(key interface{}) {
+func (t *Transaction) SignWithClang(key account.Signer) {
 	t.sign(key, false)
 }
 
@@ -632,12 +471,30 @@ func (t *Transaction) Serialize() interface{} {
 	if t.cName != "" {
 		param["cName"] = t.cName
 	}
+	t.warnUnsignedFields()
 	return param
 }
 
-// SerializeToString serialize the tx instance to json string
+// warnUnsignedFields logs the optional fields which are set but not signed in the tx version,
+// they are still sent, but the nodes of the version do not support them
+func (t *Transaction) warnUnsignedFields() {
+	fields := getTxVersionSpec(t.txVersion).fields
+	if fields&txFieldExtraID == 0 && (len(t.extraIdInt64) > 0 || len(t.extraIdString) > 0) {
+		logger.Warningf("extraId is not supported by tx version %s, it is sent without being signed", t.txVersion)
+	}
+	if fields&txFieldCName == 0 && t.cName != "" {
+		logger.Warningf("cName is not supported by tx version %s, it is sent without being signed", t.txVersion)
+	}
+}
+
+// SerializeToString serialize the tx instance to json string by MarshalJSON
 func (t *Transaction) SerializeToString() string {
-	return ""
+	data, err := t.MarshalJSON()
+	if err != nil {
+		logger.Errorf("serialize transaction error: %v", err)
+		return ""
+	}
+	return string(data)
 }
 
 func (t *Transaction) SetFrom(from string) {
@@ -724,6 +581,17 @@ func (t *Transaction) SetIsByName(isByName bool) {
 	t.isByName = isByName
 }
 
+// GetHash return the hash of the signed transaction computed locally, which is the hash returned by node
+// when the transaction is sent, so it can be persisted before the transaction is sent. The tx version of
+// the transaction should be the one of the chain, as it is by default. Empty string is returned if the hash
+// can not be computed, such as the transaction is private or its vm type is unknown.
+func (t *Transaction) GetHash() string {
+	if t.isPrivateTx {
+		return ""
+	}
+	return t.GetTransactionHash(getTxVersionSpec(t.txVersion).gasLimit)
+}
+
 func (t *Transaction) GetTransactionHash(gasLimit int64) string {
 	defaultGasPrice := int64(10000)
 	extraId, err := t.GetExtraIdString()
@@ -779,7 +647,7 @@ func (t *Transaction) GetTransactionHash(gasLimit int64) string {
 		return ""
 	}
 
-	if GetTxVersionInt(t.txVersion) >= TxVersion25 {
+	if getTxVersionSpec(t.txVersion).timestampHash {
 		binary.BigEndian.PutUint64(h[0:TimeLength], uint64(t.timestamp))
 	}
 	return "0x" + common.Bytes2Hex(h)
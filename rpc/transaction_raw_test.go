@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hyperchain/gosdk/account"
+	"github.com/hyperchain/gosdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFullTransaction(t *testing.T) *Transaction {
+	kvExtra := NewKVExtra()
+	assert.Nil(t, kvExtra.AddKV("count", 12345678901234))
+	assert.Nil(t, kvExtra.AddKV("name", "gosdk"))
+	tx := NewTransaction(testFrom).Invoke("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", common.Hex2Bytes("a9059cbb00000001")).KVExtra(kvExtra)
+	tx.SetExtraIDInt64(1, 2)
+	tx.SetExtraIDString("order-1")
+	tx.SetCName("token")
+	tx.SetParticipants([]string{"node1", "node2"})
+	tx.setTxVersion("2.5")
+	tx.SetSignature("0x00signature")
+	return tx
+}
+
+func TestTransaction_MarshalJSON(t *testing.T) {
+	tx := newFullTransaction(t)
+	data, err := json.Marshal(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), tx.SerializeToString())
+
+	var restored Transaction
+	assert.Nil(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, tx.kvExtra.Stringify(), restored.kvExtra.Stringify())
+	restored.kvExtra = tx.kvExtra
+	assert.Equal(t, tx, &restored)
+	assert.Equal(t, needHashString(tx), needHashString(&restored))
+	assert.Equal(t, tx.GetHash(), restored.GetHash())
+
+	_, err = ParseTransaction([]byte(`{"kvExtra":"{"}`))
+	assert.NotNil(t, err)
+}
+
+func TestTransaction_MarshalProto(t *testing.T) {
+	tx := newFullTransaction(t)
+	tx.isPrivateTx = true
+	data, err := tx.MarshalProto()
+	assert.Nil(t, err)
+
+	restored, err := ParseTransaction(data)
+	assert.Nil(t, err)
+	assert.Equal(t, tx.kvExtra.Stringify(), restored.kvExtra.Stringify())
+	restored.kvExtra = tx.kvExtra
+	assert.Equal(t, tx, restored)
+}
+
+func TestSendRawTransaction(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+
+	// the transaction is built by an online host
+	unsigned := NewTransaction(testFrom).Deploy("6060")
+	unsigned.setTxVersion(rp.txVersion)
+	data, err := unsigned.MarshalProto()
+	assert.Nil(t, err)
+	_, stdErr := rp.SendRawTransaction(data)
+	assert.True(t, errors.Is(stdErr, ErrSystem))
+
+	// then signed by an offline host
+	tx, err := ParseTransaction(data)
+	assert.Nil(t, err)
+	key, err := account.NewAccountFromPriv(testPriv)
+	assert.Nil(t, err)
+	tx.Sign(key)
+	signed, err := tx.MarshalJSON()
+	assert.Nil(t, err)
+
+	receipt, stdErr := rp.SendRawTransaction(signed)
+	assert.Nil(t, stdErr)
+	assert.Equal(t, tx.GetHash(), receipt.TxHash)
+	assert.NotEmpty(t, receipt.ContractAddress)
+	assert.Equal(t, 1, srv.Requests(CONTRACT+"deployContract"))
+
+	// the signature is invalid for another tx version
+	tx.setTxVersion("1.0")
+	_, stdErr = rp.SendRawTransaction([]byte(tx.SerializeToString()))
+	assert.True(t, errors.Is(stdErr, ErrSystem))
+	assert.Equal(t, 1, srv.Requests(CONTRACT+"deployContract"))
+}
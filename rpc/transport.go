@@ -0,0 +1,240 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Transport sends json rpc calls to hyperchain and returns the result of the response.
+// A response with non-zero code is returned as ServerError.
+// Implementations must be safe for concurrent use.
+type Transport interface {
+	Call(ctx context.Context, req *JSONRequest) (json.RawMessage, StdError)
+	Close() StdError
+}
+
+// Transport set the transport used by calls of rpc, http transport is used if it is not set.
+// The transport is closed by rpc.Close().
+func (rpc *RPC) Transport(transport Transport) *RPC {
+	rpc.transport = transport
+	return rpc
+}
+
+// getTransport return the transport set by user, or the http transport of rpc
+func (rpc *RPC) getTransport() Transport {
+	if rpc.transport != nil {
+		return rpc.transport
+	}
+	return &httpTransport{hrm: &rpc.hrm}
+}
+
+/*---------------------------------- http ----------------------------------*/
+
+// httpTransport posts every call to a node chosen by the node selector
+type httpTransport struct {
+	hrm *httpRequestManager
+}
+
+// NewHTTPTransport return the transport which posts every call in a http request
+// to the nodes of rpc, it is the default transport
+func (rpc *RPC) NewHTTPTransport() Transport {
+	return &httpTransport{hrm: &rpc.hrm}
+}
+
+func (ht *httpTransport) Call(ctx context.Context, req *JSONRequest) (json.RawMessage, StdError) {
+	call := &Call{Request: req}
+	if err := ht.hrm.do(ctx, call, GENERAL, nil); err != nil {
+		return nil, err
+	}
+	return call.result()
+}
+
+// Close does nothing, idle connections are closed by rpc.Close()
+func (ht *httpTransport) Close() StdError {
+	return nil
+}
+
+/*---------------------------------- websocket ----------------------------------*/
+
+// wsTransport multiplexes concurrent calls over one websocket connection,
+// responses are matched to calls by json rpc id
+type wsTransport struct {
+	hrm    *httpRequestManager
+	url    string
+	conn   *websocket.Conn
+	nextID int64
+
+	writeMutex sync.Mutex
+
+	mutex   sync.Mutex
+	pending map[int]chan []byte
+	err     error
+	done    chan struct{}
+}
+
+// NewWebSocketTransport dial the websocket port of the node and return a transport
+// sending all calls over the connection, nodeIndex starts from 1 as WebSocketClient.
+// Calls fail with GetResponseError after the connection is broken, a new transport should be created then.
+func (rpc *RPC) NewWebSocketTransport(nodeIndex int) (Transport, StdError) {
+	if nodeIndex <= 0 || nodeIndex > len(rpc.hrm.nodes) {
+		return nil, NewSystemError(fmt.Errorf("node index out of range, suppose to be in [1, %d]", len(rpc.hrm.nodes)))
+	}
+	header, stdErr := rpc.hrm.wsHeader(nodeIndex - 1)
+	if stdErr != nil {
+		return nil, stdErr
+	}
+	url := rpc.hrm.nodes[nodeIndex-1].wsURL
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		return nil, NewGetResponseError(err)
+	}
+
+	wt := &wsTransport{
+		hrm:     &rpc.hrm,
+		url:     url,
+		conn:    conn,
+		pending: make(map[int]chan []byte),
+		done:    make(chan struct{}),
+	}
+	go wt.readLoop()
+	go wt.heartbeat()
+	return wt, nil
+}
+
+// Call passes the call through the interceptors of rpc, call.URL is informational
+// as the call is always sent over the connection of the transport
+func (wt *wsTransport) Call(ctx context.Context, req *JSONRequest) (json.RawMessage, StdError) {
+	if ctx.Err() != nil {
+		return nil, NewRequestCanceledError(ctx.Err())
+	}
+
+	// the request may be reused by caller, so the id is set on a copy
+	r := *req
+	call := &Call{Request: &r, URL: wt.url}
+	if err := wt.hrm.intercept(ctx, call, wt.send); err != nil {
+		return nil, err
+	}
+	return call.result()
+}
+
+// send writes the call to the connection and waits for the response of the same id
+func (wt *wsTransport) send(ctx context.Context, call *Call) StdError {
+	id := int(atomic.AddInt64(&wt.nextID, 1))
+	call.Request.ID = id
+	body, stdErr := call.Body()
+	if stdErr != nil {
+		return stdErr
+	}
+
+	ch := make(chan []byte, 1)
+	wt.mutex.Lock()
+	if wt.err != nil {
+		wt.mutex.Unlock()
+		return NewGetResponseError(wt.err)
+	}
+	wt.pending[id] = ch
+	wt.mutex.Unlock()
+	defer wt.forget(id)
+
+	logger.Debug("[URL]:", wt.url)
+	logger.Debug("[REQUEST]:", string(body))
+
+	wt.writeMutex.Lock()
+	err := wt.conn.WriteMessage(websocket.TextMessage, body)
+	wt.writeMutex.Unlock()
+	if err != nil {
+		return NewGetResponseError(err)
+	}
+
+	select {
+	case call.Response = <-ch:
+		return nil
+	case <-ctx.Done():
+		return NewRequestCanceledError(ctx.Err())
+	case <-wt.done:
+		wt.mutex.Lock()
+		defer wt.mutex.Unlock()
+		return NewGetResponseError(wt.err)
+	}
+}
+
+func (wt *wsTransport) forget(id int) {
+	wt.mutex.Lock()
+	defer wt.mutex.Unlock()
+	delete(wt.pending, id)
+}
+
+// readLoop dispatches responses to waiting calls until the connection is closed
+func (wt *wsTransport) readLoop() {
+	for {
+		_, data, err := wt.conn.ReadMessage()
+		if err != nil {
+			wt.fail(err)
+			return
+		}
+		logger.Debug("[RESPONSE]:", string(data))
+
+		var resp struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			logger.Errorf("websocket transport of %s receive invalid response: %v", wt.url, err)
+			continue
+		}
+		wt.mutex.Lock()
+		ch, ok := wt.pending[resp.ID]
+		delete(wt.pending, resp.ID)
+		wt.mutex.Unlock()
+		if ok {
+			ch <- data
+		}
+	}
+}
+
+func (wt *wsTransport) heartbeat() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-wt.done:
+			return
+		case <-ticker.C:
+			wt.writeMutex.Lock()
+			err := wt.conn.WriteControl(websocket.PingMessage, []byte("heart beat"), time.Now().Add(5*time.Second))
+			wt.writeMutex.Unlock()
+			if err != nil {
+				logger.Errorf("websocket transport of %s heart beat error: %v", wt.url, err)
+			}
+		}
+	}
+}
+
+// fail records the first error of the connection and wakes up all waiting calls
+func (wt *wsTransport) fail(err error) {
+	wt.mutex.Lock()
+	defer wt.mutex.Unlock()
+	if wt.err != nil {
+		return
+	}
+	wt.err = err
+	close(wt.done)
+}
+
+// Close closes the connection, waiting calls fail with GetResponseError
+func (wt *wsTransport) Close() StdError {
+	wt.writeMutex.Lock()
+	_ = wt.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "ok"), time.Now().Add(time.Second))
+	wt.writeMutex.Unlock()
+	wt.fail(errors.New("websocket transport is closed"))
+	if err := wt.conn.Close(); err != nil {
+		return NewSystemError(err)
+	}
+	return nil
+}
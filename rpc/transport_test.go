@@ -0,0 +1,49 @@
+package rpc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebSocketTransport(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+
+	transport, err := rp.NewWebSocketTransport(2)
+	assert.Nil(t, err)
+	rp.Transport(transport)
+
+	for i := 0; i < 5; i++ {
+		_, err = rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", int64(i)))
+		assert.Nil(t, err)
+	}
+
+	// concurrent calls get their own responses
+	var wg sync.WaitGroup
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func(number uint64) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				block, err := rp.GetBlockByNumber(number, true)
+				assert.Nil(t, err)
+				assert.Equal(t, number, block.Number)
+			}
+		}(uint64(i))
+	}
+	wg.Wait()
+	assert.Equal(t, 50, srv.NodeRequests(1, BLOCK+"getBlockByNumber"))
+
+	_, err = rp.GetBlockByNumber(100, true)
+	assert.Equal(t, DataNotExistCode, err.Code())
+
+	assert.Nil(t, transport.Close())
+	_, err = rp.GetNodes()
+	assert.Equal(t, GetResponseErrorCode, err.Code())
+
+	// http transport is used by default
+	_, err = rp.Transport(nil).GetNodes()
+	assert.Nil(t, err)
+}
@@ -0,0 +1,249 @@
+package rpc
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperchain/gosdk/common"
+)
+
+// txField is an optional field of transaction which is signed since some tx version
+type txField uint
+
+const (
+	txFieldExtraID txField = 1 << iota
+	txFieldCName
+)
+
+// txVersionSpec declares how the transactions of a tx version are signed, hashed and serialized
+type txVersionSpec struct {
+	version string
+	id      TxVersionInt
+	// processor writes the needHash string which is signed
+	processor processor
+	// gasLimit is the gas limit of the transaction value hashed by GetHash
+	gasLimit int64
+	// timestampHash replaces the first TimeLength bytes of the hash by the timestamp
+	timestampHash bool
+	// fields are the optional fields signed, the others are sent with a warning if they are set
+	fields txField
+}
+
+var (
+	// txVersionSpecs is the registry of tx versions
+	txVersionSpecs = make(map[string]*txVersionSpec)
+	// sortedTxVersionSpecs are the registered specs in ascending order of version
+	sortedTxVersionSpecs []*txVersionSpec
+)
+
+func init() {
+	hyperchain := newProcessorWithHyperchain()
+	flato := newProcessorWithFlato()
+	flato21 := newProcessorWithFlato21()
+	flato22 := newProcessorWithFlato22()
+
+	registerTxVersion(&txVersionSpec{version: "1.0", id: TxVersion10, processor: hyperchain, gasLimit: DefaultTxGasLimit})
+	registerTxVersion(&txVersionSpec{version: "2.0", id: TxVersion20, processor: flato, gasLimit: DefaultTxGasLimitV2})
+	// extraId is signed since 2.1
+	registerTxVersion(&txVersionSpec{version: "2.1", id: TxVersion21, processor: flato21, gasLimit: DefaultTxGasLimitV2,
+		fields: txFieldExtraID})
+	// cName is signed since 2.2, the later versions sign the same fields
+	for _, spec := range []*txVersionSpec{
+		{version: "2.2", id: TxVersion22},
+		{version: "2.3", id: TxVersion23},
+		{version: "2.4", id: TxVersion24},
+		// the hash is prefixed by the timestamp since 2.5
+		{version: "2.5", id: TxVersion25, timestampHash: true},
+		{version: "2.6", id: TxVersion26, timestampHash: true},
+		{version: "2.7", id: TxVersion27, timestampHash: true},
+		{version: "2.8", id: TxVersion28, timestampHash: true},
+		{version: "2.9", id: TxVersion29, timestampHash: true},
+	} {
+		spec.processor = flato22
+		spec.gasLimit = DefaultTxGasLimitV2
+		spec.fields = txFieldExtraID | txFieldCName
+		registerTxVersion(spec)
+	}
+}
+
+// registerTxVersion adds spec to the registry
+func registerTxVersion(spec *txVersionSpec) {
+	if _, ok := txVersionSpecs[spec.version]; ok {
+		panic("tx version " + spec.version + " is registered twice")
+	}
+	txVersionSpecs[spec.version] = spec
+	sortedTxVersionSpecs = append(sortedTxVersionSpecs, spec)
+	sort.Slice(sortedTxVersionSpecs, func(i, j int) bool {
+		return compareTxVersion(sortedTxVersionSpecs[i].version, sortedTxVersionSpecs[j].version) < 0
+	})
+}
+
+// getTxVersionSpec return the spec of txVersion. If txVersion is not registered, the spec of
+// the latest version before it is returned, or the one of 1.0 if there is none.
+func getTxVersionSpec(txVersion string) *txVersionSpec {
+	if spec, ok := txVersionSpecs[txVersion]; ok {
+		return spec
+	}
+	spec := sortedTxVersionSpecs[0]
+	for _, s := range sortedTxVersionSpecs {
+		if compareTxVersion(s.version, txVersion) > 0 {
+			break
+		}
+		spec = s
+	}
+	return spec
+}
+
+// compareTxVersion compares tx versions like "2.10" and "2.9" by their numbers, missing parts are 0
+// and the parts which are not numbers are compared as strings
+func compareTxVersion(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for len(as) < len(bs) {
+		as = append(as, "0")
+	}
+	for len(bs) < len(as) {
+		bs = append(bs, "0")
+	}
+	for i := range as {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr != nil || bErr != nil:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		}
+	}
+	return 0
+}
+
+// GetTxVersionInt return the TxVersionInt of tx version string, TxVersion10 if it is unknown
+func GetTxVersionInt(txVersionStr string) TxVersionInt {
+	if spec, ok := txVersionSpecs[txVersionStr]; ok {
+		return spec.id
+	}
+	return TxVersion10
+}
+
+// needHashString construct a stirng that need to hash
+func needHashString(t *Transaction) string {
+	p := getProcessor(t.txVersion)
+	sb := &strings.Builder{}
+	p.process(sb, t)
+	return sb.String()
+}
+
+type processor interface {
+	process(buffer *strings.Builder, t *Transaction)
+}
+
+type processorWithHyperchain struct{}
+
+func newProcessorWithHyperchain() *processorWithHyperchain {
+	return &processorWithHyperchain{}
+}
+
+func (p *processorWithHyperchain) process(buffer *strings.Builder, t *Transaction) {
+	var payload string
+	if t.isValue {
+		payload = "0x" + strconv.FormatInt(t.value, 16)
+	} else if (t.isMaintain && t.opcode != 1) || t.payload == "" {
+		payload = "0x0"
+	} else {
+		payload = strings.ToLower(common.StringToHex(t.payload))
+	}
+
+	writeBaseFiled(buffer, t, payload, false)
+}
+
+type processorWithFlato struct{}
+
+func newProcessorWithFlato() *processorWithFlato {
+	return &processorWithFlato{}
+}
+
+func (p *processorWithFlato) process(buffer *strings.Builder, t *Transaction) {
+	var payload string
+	if (t.isMaintain && t.opcode != 1) || t.payload == "" {
+		payload = "0x0"
+	} else {
+		payload = strings.ToLower(common.StringToHex(t.payload))
+	}
+
+	writeBaseFiled(buffer, t, payload, true)
+	buffer.WriteString("&version=")
+	buffer.WriteString(t.txVersion)
+}
+
+type processorWithFlato21 struct {
+	flato *processorWithFlato
+}
+
+func newProcessorWithFlato21() *processorWithFlato21 {
+	return &processorWithFlato21{
+		flato: newProcessorWithFlato(),
+	}
+}
+
+func (p *processorWithFlato21) process(buffer *strings.Builder, t *Transaction) {
+	p.flato.process(buffer, t)
+	strExtraId, err := t.GetExtraIdString()
+	if err != nil {
+		logger.Warning("GetExtraIdString failed: " + err.Error())
+	}
+	buffer.WriteString("&extraid=")
+	buffer.WriteString(strExtraId)
+}
+
+type processorWithFlato22 struct {
+	flato21 *processorWithFlato21
+}
+
+func newProcessorWithFlato22() *processorWithFlato22 {
+	return &processorWithFlato22{
+		flato21: newProcessorWithFlato21(),
+	}
+}
+
+func (p *processorWithFlato22) process(buffer *strings.Builder, t *Transaction) {
+	p.flato21.process(buffer, t)
+	buffer.WriteString("&cname=")
+	buffer.WriteString(t.cName)
+}
+
+// getProcessor return the processor of the spec of txVersion
+func getProcessor(txVersion string) processor {
+	return getTxVersionSpec(txVersion).processor
+}
+
+func writeBaseFiled(buffer *strings.Builder, t *Transaction, payload string, hasPayload bool) {
+	buffer.WriteString("from=")
+	buffer.WriteString(common.StringToHex(strings.ToLower(t.from)))
+	buffer.WriteString("&to=")
+	buffer.WriteString(common.StringToHex(strings.ToLower(t.to)))
+	if hasPayload {
+		buffer.WriteString("&value=0x")
+		buffer.WriteString(strconv.FormatInt(t.value, 16))
+	}
+	if hasPayload {
+		buffer.WriteString("&payload=")
+	} else {
+		buffer.WriteString("&value=")
+	}
+	buffer.WriteString(payload)
+	buffer.WriteString("&timestamp=0x")
+	buffer.WriteString(strconv.FormatInt(t.timestamp, 16))
+	buffer.WriteString("&nonce=0x")
+	buffer.WriteString(strconv.FormatInt(t.nonce, 16))
+	buffer.WriteString("&opcode=")
+	buffer.WriteString(strconv.FormatInt(t.opcode, 16))
+	buffer.WriteString("&extra=")
+	buffer.WriteString(t.extra)
+	buffer.WriteString("&vmtype=")
+	buffer.WriteString(t.vmType)
+}
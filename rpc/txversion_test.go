@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperchain/gosdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+const (
+	txVersionGoldenFile = "tx_versions.json"
+	// txVersionBaselineFile are the vectors produced by the sdk before the registry, they are not updated
+	txVersionBaselineFile  = "tx_versions_baseline.json"
+	txVersionTestSignature = "0x0032d4f6e0b2a3ae2a8b1b6b1d8f5f0a6c9c0e7a1a2b4d9c0f1e2d3c4b5a6978" +
+		"8796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0a1b2c3d4e5f60718293a4b5c"
+)
+
+// txVersionVector is the golden needHash string, hash and serialized params of a transaction in a tx version
+type txVersionVector struct {
+	Version    string                 `json:"version"`
+	Case       string                 `json:"case"`
+	NeedHash   string                 `json:"needHash"`
+	Hash       string                 `json:"hash"`
+	Serialized map[string]interface{} `json:"serialized"`
+}
+
+// txVersionCases are the transactions of the golden vectors, signed by a fixed signature
+var txVersionCases = []struct {
+	name  string
+	build func() *Transaction
+}{
+	{"transfer", func() *Transaction {
+		return NewTransaction("0x000f1a7a08ccc48e5d30f80850cf1cf283aa3abd").
+			Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 100)
+	}},
+	{"invoke", func() *Transaction {
+		tx := NewTransaction("0x000f1a7a08ccc48e5d30f80850cf1cf283aa3abd").
+			Invoke("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", common.Hex2Bytes("60fe47b1000000000000000000000000000000000000000000000000000000000000002a")).
+			Extra("extra")
+		tx.SetExtraIDInt64(1, 2)
+		tx.SetExtraIDString("a", "b")
+		return tx
+	}},
+	{"invokeByName", func() *Transaction {
+		return NewTransaction("0x000f1a7a08ccc48e5d30f80850cf1cf283aa3abd").
+			InvokeByName("Fibonacci", []byte("fefffbce00000001")).VMType(HVM)
+	}},
+	{"freeze", func() *Transaction {
+		return NewTransaction("0x000f1a7a08ccc48e5d30f80850cf1cf283aa3abd").
+			Maintain(2, "0x6201cb0448964ac597faf6fdf1f472edf2a22b89", "")
+	}},
+}
+
+func buildTxVersionVectors(t *testing.T) []txVersionVector {
+	var vectors []txVersionVector
+	for _, spec := range sortedTxVersionSpecs {
+		for _, c := range txVersionCases {
+			tx := c.build().Nonce(8279451374862474).Timestamp(1581776744000000000).
+				Signature(txVersionTestSignature)
+			tx.setTxVersion(spec.version)
+
+			// decode the params like node does, so that they compare with the golden file
+			data, err := json.Marshal(tx.Serialize())
+			assert.Nil(t, err)
+			var serialized map[string]interface{}
+			assert.Nil(t, decodeGolden(data, &serialized))
+			vectors = append(vectors, txVersionVector{
+				Version:    spec.version,
+				Case:       c.name,
+				NeedHash:   needHashString(tx),
+				Hash:       tx.GetHash(),
+				Serialized: serialized,
+			})
+		}
+	}
+	return vectors
+}
+
+// decodeGolden decode the numbers as json.Number, which keeps the timestamps exact
+func decodeGolden(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func TestTxVersion_Golden(t *testing.T) {
+	vectors := buildTxVersionVectors(t)
+	path := filepath.Join("testdata", txVersionGoldenFile)
+	if *updateGolden {
+		buf := &bytes.Buffer{}
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		assert.Nil(t, encoder.Encode(vectors))
+		assert.Nil(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var golden []txVersionVector
+	assert.Nil(t, decodeGolden(data, &golden))
+	assert.Equal(t, len(golden), len(vectors))
+	for i := range golden {
+		if i >= len(vectors) {
+			break
+		}
+		assert.Equal(t, golden[i], vectors[i], "tx version %s, case %s", golden[i].Version, golden[i].Case)
+	}
+}
+
+func TestTxVersion_Baseline(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", txVersionBaselineFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var baseline []txVersionVector
+	assert.Nil(t, decodeGolden(data, &baseline))
+	assert.NotEmpty(t, baseline)
+
+	vectors := make(map[string]txVersionVector)
+	for _, v := range buildTxVersionVectors(t) {
+		vectors[v.Version+"/"+v.Case] = v
+	}
+	for _, expect := range baseline {
+		actual, ok := vectors[expect.Version+"/"+expect.Case]
+		if !assert.True(t, ok, "tx version %s, case %s", expect.Version, expect.Case) {
+			continue
+		}
+		assert.Equal(t, expect.NeedHash, actual.NeedHash, "tx version %s, case %s", expect.Version, expect.Case)
+		assert.Equal(t, expect.Serialized, actual.Serialized, "tx version %s, case %s", expect.Version, expect.Case)
+	}
+}
+
+func TestTxVersion_Registry(t *testing.T) {
+	for _, v := range []string{"1.0", "2.0", "2.1", "2.2", "2.3", "2.4", "2.5", "2.6", "2.7", "2.8", "2.9"} {
+		spec, ok := txVersionSpecs[v]
+		assert.True(t, ok, v)
+		assert.Equal(t, spec.id, GetTxVersionInt(v))
+		assert.Equal(t, spec.id >= TxVersion25, spec.timestampHash, v)
+	}
+	assert.Equal(t, TxVersion10, GetTxVersionInt("3.0"))
+
+	// unknown versions fall back to the latest one before them
+	for v, expect := range map[string]string{
+		"":     "1.0",
+		"0.9":  "1.0",
+		"1.8":  "1.0",
+		"2":    "2.0",
+		"2.10": "2.9",
+		"3.0":  "2.9",
+	} {
+		assert.Equal(t, expect, getTxVersionSpec(v).version, v)
+	}
+
+	assert.True(t, compareTxVersion("2.10", "2.9") > 0)
+	assert.True(t, compareTxVersion("2.1", "2.10") < 0)
+	assert.Equal(t, 0, compareTxVersion("2", "2.0"))
+}
@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -8,11 +10,12 @@ import (
 
 // reponse codes
 const (
-	SystemErrorCode         = -9996
-	AsnycRequestErrorCode   = -9997
-	RequestTimeoutErrorCode = -9998
-	GetResponseErrorCode    = -9999
-	SuccessCode             = 0
+	RequestCanceledErrorCode = -9994
+	SystemErrorCode          = -9996
+	AsnycRequestErrorCode    = -9997
+	RequestTimeoutErrorCode  = -9998
+	GetResponseErrorCode     = -9999
+	SuccessCode              = 0
 	//InvalidJSONCode             = -32700
 	//InvalidRequestCode          = -32600
 	MethodNotExistOrInvalidCode = -32601
@@ -20,6 +23,7 @@ const (
 	//JSONRPCInternalErrorCode    = -32603
 	DataNotExistCode          = -32001
 	BalanceInsufficientCode   = -32002
+	ContractInvokeErrorCode   = -32005
 	SystemBusyCode            = -32006
 	DuplicateTransactionsCode = -32007
 )
@@ -548,10 +552,17 @@ type StdError interface {
 	Code() int
 }
 
-// RetError is packaged ret code and message
+// RetError is packaged ret code and message.
+// It matches the sentinel error of its code by errors.Is, and unwraps to the underlying cause.
 type RetError struct {
 	code    int
 	message string
+	cause   error
+	// kind is the sentinel error matched instead of the sentinel of code
+	kind *RetError
+	// method and url of the request which failed
+	method string
+	url    string
 }
 
 func (re *RetError) String() string {
@@ -567,6 +578,45 @@ func (re *RetError) Code() int {
 	return re.code
 }
 
+// Unwrap return the underlying error, nil if the error is returned by node
+func (re *RetError) Unwrap() error {
+	return re.cause
+}
+
+// Method return the json rpc method of the failed request, empty if the error is not caused by a request
+func (re *RetError) Method() string {
+	return re.method
+}
+
+// URL return the url of the node the failed request was sent to, empty if no node was chosen
+func (re *RetError) URL() string {
+	return re.url
+}
+
+// Is reports whether re matches the sentinel error target
+func (re *RetError) Is(target error) bool {
+	t, ok := target.(*RetError)
+	return ok && t == re.sentinel()
+}
+
+// IsRetryable return whether the request may succeed if it is sent again later
+func (re *RetError) IsRetryable() bool {
+	switch re.sentinel() {
+	case ErrDataNotExist, ErrSystemBusy, ErrNodeUnavailable, ErrQueueFull:
+		return true
+	default:
+		return false
+	}
+}
+
+// sentinel return the sentinel error re matches, nil if there is none
+func (re *RetError) sentinel() *RetError {
+	if re.kind != nil {
+		return re.kind
+	}
+	return sentinels[re.code]
+}
+
 // NewServerError is used to construct RetError
 func NewServerError(c int, msg string) StdError {
 	return &RetError{
@@ -583,6 +633,7 @@ func NewSystemError(e error) StdError {
 	return &RetError{
 		code:    SystemErrorCode,
 		message: e.Error(),
+		cause:   e,
 	}
 }
 
@@ -594,7 +645,25 @@ func NewRequestTimeoutError(e error) StdError {
 	return &RetError{
 		code:    RequestTimeoutErrorCode,
 		message: e.Error(),
+		cause:   e,
+	}
+}
+
+// NewRequestCanceledError is used to construct StdError when the request context is canceled or its deadline is exceeded,
+// the error matches ErrTimeout if the deadline is exceeded, otherwise ErrCanceled
+func NewRequestCanceledError(e error) StdError {
+	if e == nil {
+		return nil
+	}
+	re := &RetError{
+		code:    RequestCanceledErrorCode,
+		message: e.Error(),
+		cause:   e,
+	}
+	if errors.Is(e, context.DeadlineExceeded) {
+		re.kind = ErrTimeout
 	}
+	return re
 }
 
 // NewGetResponseError is used to construct StdError
@@ -605,6 +674,7 @@ func NewGetResponseError(e error) StdError {
 	return &RetError{
 		code:    GetResponseErrorCode,
 		message: e.Error(),
+		cause:   e,
 	}
 }
 
@@ -2,22 +2,36 @@ package rpc
 
 import (
 	"bytes"
-	"crypto/rand"
 	"errors"
 	"github.com/hyperchain/gosdk/account"
 	"github.com/hyperchain/gosdk/common"
-	gm "github.com/ultramesh/crypto-gm"
-	"github.com/ultramesh/crypto-standard/asym"
-	"github.com/ultramesh/crypto-standard/hash"
 )
 
-// sign use key to sign need hash string
-func sign(key interface{}, needHash string, batch bool, isKPIAccount bool) (string, error) {
-	h, err := getHash(key, needHash)
+// flags of signature, which is the first byte of the signature of a transaction
+const (
+	// signFlagECDSA is followed by a recoverable secp256k1 signature
+	signFlagECDSA byte = 0x00
+	// signFlagSM2 is followed by the public key and the signature
+	signFlagSM2 byte = 0x01
+	// signFlagED25519 is followed by the public key and the signature
+	signFlagED25519 byte = 0x02
+	// signFlagPKI is followed by the signature, the public key is in the certificate of account
+	signFlagPKI byte = 0x04
+	// signFlagECDSAR1 is followed by the public key and the signature
+	signFlagECDSAR1 byte = 0x05
+)
+
+// sign use signer to sign need hash string
+func sign(signer account.Signer, needHash string, batch bool) (string, error) {
+	if signer == nil {
+		logger.Error("unsupported sign type")
+		return "", errors.New("key is nil")
+	}
+	h, err := account.Digest(signer, []byte(needHash))
 	if err != nil {
 		return "", err
 	}
-	sig, err := genSignature(key.(account.Key), h, batch, isKPIAccount)
+	sig, err := genSignature(signer, h, batch)
 	if err != nil {
 		logger.Error("ecdsa signature error")
 		return "", errors.New("gen signature error")
@@ -25,24 +39,6 @@ func sign(key interface{}, needHash string, batch bool, isKPIAccount bool) (stri
 	return common.ToHex(sig), nil
 }
 
-// getHash use key to hash need hash string
-func getHash(key interface{}, needHash string) ([]byte, error) {
-	var h []byte
-	switch k := key.(type) {
-	case *account.ECDSAKey:
-		h, _ = hash.NewHasher(hash.KECCAK_256).Hash([]byte(needHash))
-	case *account.SM2Key:
-		h = gm.HashBeforeSM2(&k.PublicKey, []byte(needHash))
-	case *account.ED25519Key:
-		h = []byte(needHash)
-	default:
-		logger.Error("unsupported sign type")
-		return nil, errors.New("unsupported sign type")
-	}
-	return h, nil
-
-}
-
 //// genSignature get a signature of gm or ecdsa
 //func genSignature(key account.Key, hash []byte, batch bool) ([]byte, StdError) {
 //	var r []byte
@@ -72,40 +68,34 @@ func getHash(key interface{}, needHash string) ([]byte, error) {
 //}
 
 // genSignature get a signature of gm or ecdsa
-func genSignature(key account.Key, hash []byte, batch, isPKIAccount bool) ([]byte, StdError) {
+func genSignature(signer account.Signer, hash []byte, batch bool) ([]byte, StdError) {
 	var r []byte
 	var e error
-	if sm2key, ok := key.(*account.SM2Key); batch && ok {
-		r, e = sm2key.SM2PrivateKey.SignBatch(rand.Reader, hash, nil)
+	if bs, ok := signer.(account.BatchSigner); batch && ok {
+		r, e = bs.SignDigestBatch(hash)
 	} else {
-		r, e = key.Sign(rand.Reader, hash, nil)
+		r, e = signer.SignDigest(hash)
 	}
 	if e != nil {
 		return nil, NewSystemError(errors.New("signature error:" + e.Error()))
 	}
-	pub, _ := key.PublicBytes()
-	switch key.(type) {
-	case *account.ECDSAKey:
+	pub, _ := signer.PublicBytes()
+	switch signer.Algorithm() {
+	case account.AlgorithmECDSAK1:
+		logger.Debug("sign type : ecdsa")
+		return append([]byte{signFlagECDSA}, r...), nil
+	case account.AlgorithmECDSAR1:
 		logger.Debug("sign type : ecdsa")
-		if isPKIAccount {
-			return bytes.Join([][]byte{{0x04}, r}, nil), nil
-		}
-		if key.(*account.ECDSAKey).AlgorithmType() == asym.AlgoP256R1 {
-			return bytes.Join([][]byte{{0x05}, pub, r}, nil), nil
-		}
-		return append([]byte{0x00}, r...), nil
-	case *account.SM2Key:
+		return bytes.Join([][]byte{{signFlagECDSAR1}, pub, r}, nil), nil
+	case account.AlgorithmSM2:
 		logger.Debug("sign type : sm2")
-		if isPKIAccount {
-			return bytes.Join([][]byte{{0x04}, r}, nil), nil
-		}
-		return bytes.Join([][]byte{{0x01}, pub, r}, nil), nil
-	case *account.ED25519Key:
+		return bytes.Join([][]byte{{signFlagSM2}, pub, r}, nil), nil
+	case account.AlgorithmED25519:
 		logger.Debug("sign type : ed25519")
-		if isPKIAccount {
-			return nil, NewSystemError(errors.New("it doesn't support ed25519 cert"))
-		}
-		return bytes.Join([][]byte{{0x02}, pub, r}, nil), nil
+		return bytes.Join([][]byte{{signFlagED25519}, pub, r}, nil), nil
+	case account.AlgorithmPKIECDSA, account.AlgorithmPKISM2:
+		logger.Debug("sign type : pki")
+		return bytes.Join([][]byte{{signFlagPKI}, r}, nil), nil
 	default:
 		return nil, NewSystemError(errors.New("signature type error"))
 	}
@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperchain/gosdk/common"
+	gm "github.com/ultramesh/crypto-gm"
+	"github.com/ultramesh/crypto-standard/asym"
+	"github.com/ultramesh/crypto-standard/ed25519"
+	"github.com/ultramesh/crypto-standard/hash"
+	"github.com/ultramesh/flato-msp-cert/primitives/x509"
+)
+
+const (
+	// length of uncompressed public key of ECDSA and SM2
+	ecPublicKeyLength      = 65
+	ed25519PublicKeyLength = 32
+)
+
+// ErrInvalidSignature is wrapped by the errors of signature verification
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Verify verify the signature of the transaction against its from locally, so an invalid transaction can be
+// rejected before it is sent. It supports the signatures of ECDSA (secp256k1 and secp256r1), SM2 and ED25519
+// keys signed by Sign, SignWithBatchFlag and SignWithClang. The signature of PKI account does not contain
+// the public key, use VerifyWithCert instead. The returned error wraps ErrInvalidSignature.
+func (t *Transaction) Verify() error {
+	return VerifySignature(needHashString(t), t.signature, t.from, nil)
+}
+
+// VerifyWithCert is the same as Verify, but verify the signature of PKI account by its certificate,
+// whose common name is the address of account
+func (t *Transaction) VerifyWithCert(cert *x509.Certificate) error {
+	return VerifySignature(needHashString(t), t.signature, t.from, cert)
+}
+
+// VerifySignature verify the signature of needHash, the string signed by Transaction.Sign, against the address from.
+// cert is the certificate of PKI account, it is only used if the signature is signed by a PKI account.
+func VerifySignature(needHash, signature, from string, cert *x509.Certificate) error {
+	sig := common.FromHex(signature)
+	if len(sig) == 0 {
+		return fmt.Errorf("%w: the transaction is not signed", ErrInvalidSignature)
+	}
+	address := common.HexToAddress(from)
+	flag, body := sig[0], sig[1:]
+
+	var (
+		valid bool
+		err   error
+	)
+	switch flag {
+	case signFlagECDSA:
+		// the public key of recoverable signature is the address, the signer is recovered from signature
+		pub, perr := new(asym.ECDSAPublicKey).FromBytes(address[:], asym.AlgoP256K1Recover)
+		if perr != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSignature, perr)
+		}
+		valid, err = pub.Verify(nil, body, keccak256([]byte(needHash)))
+	case signFlagECDSAR1:
+		if len(body) <= ecPublicKeyLength {
+			return fmt.Errorf("%w: signature is too short", ErrInvalidSignature)
+		}
+		pubBytes, r := body[:ecPublicKeyLength], body[ecPublicKeyLength:]
+		if err := checkAddress(address, keccak256(pubBytes)); err != nil {
+			return err
+		}
+		pub, perr := new(asym.ECDSAPublicKey).FromBytes(pubBytes, asym.AlgoP256R1)
+		if perr != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSignature, perr)
+		}
+		valid, err = pub.Verify(nil, r, keccak256([]byte(needHash)))
+	case signFlagSM2:
+		if len(body) <= ecPublicKeyLength {
+			return fmt.Errorf("%w: signature is too short", ErrInvalidSignature)
+		}
+		pubBytes, r := body[:ecPublicKeyLength], body[ecPublicKeyLength:]
+		if err := checkAddress(address, keccak256(pubBytes)); err != nil {
+			return err
+		}
+		pub, perr := new(gm.SM2PublicKey).FromBytes(pubBytes, nil)
+		if perr != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSignature, perr)
+		}
+		// the signature signed with batch flag is verified in the same way
+		valid, err = pub.Verify(nil, r, gm.HashBeforeSM2(pub, []byte(needHash)))
+	case signFlagED25519:
+		if len(body) <= ed25519PublicKeyLength {
+			return fmt.Errorf("%w: signature is too short", ErrInvalidSignature)
+		}
+		pubBytes, r := body[:ed25519PublicKeyLength], body[ed25519PublicKeyLength:]
+		h, _ := hash.NewHasher(hash.SHA2_256).Hash(pubBytes)
+		if err := checkAddress(address, h); err != nil {
+			return err
+		}
+		pub := new(ed25519.EDDSAPublicKey)
+		pub.FromBytes(pubBytes, nil)
+		valid, err = pub.Verify(nil, r, []byte(needHash))
+	case signFlagPKI:
+		valid, err = verifyWithCert(cert, address, body, needHash)
+	default:
+		return fmt.Errorf("%w: unknown signature flag %#x", ErrInvalidSignature, flag)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !valid {
+		return fmt.Errorf("%w: signature does not match", ErrInvalidSignature)
+	}
+	return nil
+}
+
+func verifyWithCert(cert *x509.Certificate, address common.Address, r []byte, needHash string) (bool, error) {
+	if cert == nil {
+		return false, errors.New("certificate is required to verify signature of PKI account")
+	}
+	addrBytes, err := hex.DecodeString(strings.TrimPrefix(cert.Subject.CommonName, "0x"))
+	if err != nil {
+		return false, fmt.Errorf("invalid common name of certificate: %v", err)
+	}
+	if common.BytesToAddress(addrBytes) != address {
+		return false, fmt.Errorf("certificate of %s is not the one of %s", cert.Subject.CommonName, address.Hex())
+	}
+	switch pub := cert.PublicKey.(type) {
+	case *asym.ECDSAPublicKey:
+		return pub.Verify(nil, r, keccak256([]byte(needHash)))
+	case *gm.SM2PublicKey:
+		return pub.Verify(nil, r, gm.HashBeforeSM2(pub, []byte(needHash)))
+	default:
+		return false, errors.New("unknown public key type of certificate")
+	}
+}
+
+// checkAddress check whether the address is derived from the hash of public key
+func checkAddress(address common.Address, pubHash []byte) error {
+	if derived := common.BytesToAddress(pubHash[12:]); derived != address {
+		return fmt.Errorf("%w: signed by %s instead of %s", ErrInvalidSignature, derived.Hex(), address.Hex())
+	}
+	return nil
+}
+
+func keccak256(data []byte) []byte {
+	h, _ := hash.NewHasher(hash.KECCAK_256).Hash(data)
+	return h
+}
@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"crypto/x509/pkix"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperchain/gosdk/account"
+	"github.com/hyperchain/gosdk/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/ultramesh/flato-msp-cert/primitives/x509"
+)
+
+const testPriv = "a1fd6ed6225e76aac3884b5420c8cdbb4fde1db01e9ca16c9ef6fa3f3e2d2d0e"
+
+func testKeys(t *testing.T) map[string]account.Key {
+	k1, err := account.NewAccountFromPriv(testPriv)
+	assert.Nil(t, err)
+	r1, err := account.NewAccountR1FromPriv(testPriv)
+	assert.Nil(t, err)
+	sm2, err := account.NewAccountSm2FromPriv(testPriv)
+	assert.Nil(t, err)
+	accountJSON, err := account.NewAccountED25519("")
+	assert.Nil(t, err)
+	ed, err := account.GenKeyFromAccountJson(accountJSON, "")
+	assert.Nil(t, err)
+	return map[string]account.Key{
+		"ecdsa":   k1,
+		"r1":      r1,
+		"sm2":     sm2,
+		"ed25519": ed.(account.Key),
+	}
+}
+
+func TestTransaction_Verify(t *testing.T) {
+	for name, key := range testKeys(t) {
+		tx := NewTransaction(key.GetAddress().Hex()).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+		tx.Sign(key)
+		assert.Nil(t, tx.Verify(), name)
+
+		tx.SignWithBatchFlag(key)
+		assert.Nil(t, tx.Verify(), name)
+	}
+}
+
+func TestTransaction_Verify_Invalid(t *testing.T) {
+	keys := testKeys(t)
+
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	err := tx.Verify()
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+	// the public key in signature is not the one of from
+	for _, name := range []string{"sm2", "ed25519"} {
+		tx.Sign(keys[name])
+		err = tx.Verify()
+		assert.True(t, errors.Is(err, ErrInvalidSignature), name)
+		assert.Contains(t, err.Error(), "signed by", name)
+	}
+
+	tx.SetSignature("0x07" + common.Bytes2Hex(make([]byte, 65)))
+	assert.True(t, errors.Is(tx.Verify(), ErrInvalidSignature))
+	tx.SetSignature("0x05" + common.Bytes2Hex(make([]byte, 65)))
+	assert.True(t, errors.Is(tx.Verify(), ErrInvalidSignature))
+}
+
+// pkiTestSigner signs as a PKI account whose certificate contains the public key of Signer
+type pkiTestSigner struct {
+	account.Signer
+}
+
+func (s *pkiTestSigner) Algorithm() account.Algorithm {
+	return account.AlgorithmPKIECDSA
+}
+
+func TestTransaction_VerifyWithCert(t *testing.T) {
+	key := testKeys(t)["r1"].(*account.ECDSAKey)
+	signer, err := account.NewSigner(key)
+	assert.Nil(t, err)
+	tx := NewTransaction(key.GetAddress().Hex()).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	tx.Sign(&pkiTestSigner{signer})
+	assert.Equal(t, "0x04", tx.signature[:4])
+
+	cert := &x509.Certificate{
+		Subject:   pkix.Name{CommonName: common.Bytes2Hex(key.GetAddress().Bytes())},
+		PublicKey: key.Public(),
+	}
+	assert.Nil(t, tx.VerifyWithCert(cert))
+	assert.True(t, errors.Is(tx.Verify(), ErrInvalidSignature))
+
+	cert.Subject.CommonName = "6201cb0448964ac597faf6fdf1f472edf2a22b89"
+	assert.True(t, errors.Is(tx.VerifyWithCert(cert), ErrInvalidSignature))
+}
+
+func TestTransaction_SignBySigner(t *testing.T) {
+	key := testKeys(t)["sm2"]
+	local, err := account.NewSigner(key)
+	assert.Nil(t, err)
+	srv := httptest.NewServer(account.NewSignerHandler(local))
+	defer srv.Close()
+	remote, err := account.NewRemoteSigner(srv.URL, key.GetAddress().Hex(), nil)
+	assert.Nil(t, err)
+
+	tx := NewTransaction(key.GetAddress().Hex()).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	tx.Sign(remote)
+	assert.NotEmpty(t, tx.signature)
+	assert.Nil(t, tx.Verify())
+
+	tx.SignWithBatchFlag(remote)
+	assert.Nil(t, tx.Verify())
+}
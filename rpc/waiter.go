@@ -0,0 +1,241 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReceiptWaiter waits for receipts by block events of websocket instead of polling,
+// the receipt of a transaction is fetched only when the transaction appears in a new block.
+// It falls back to polling if no websocket port is configured or the subscription fails,
+// and for private transactions which are not in the block events.
+type ReceiptWaiter struct {
+	rpc   *RPC
+	wsCli *WebSocketClient
+
+	mutex     sync.Mutex
+	nodeIndex int
+	subID     SubscriptionID
+	// lost is closed when the subscription is lost, so the waiters fall back to polling
+	lost    chan struct{}
+	pending map[string]*pendingReceipt
+	closed  bool
+}
+
+// pendingReceipt is a transaction waited by one or more callers
+type pendingReceipt struct {
+	committed chan struct{}
+	waiters   int
+}
+
+// NewReceiptWaiter return a ReceiptWaiter using the nodes of rpc, block events are subscribed
+// when a receipt is waited for the first time. Close should be called when it is not used anymore.
+func (rpc *RPC) NewReceiptWaiter() *ReceiptWaiter {
+	return &ReceiptWaiter{
+		rpc:     rpc,
+		wsCli:   newWebSocketClient(&rpc.hrm),
+		pending: make(map[string]*pendingReceipt),
+	}
+}
+
+// ReceiptWaiter set the waiter used by CallByPolling and the apis sending transactions to get receipts,
+// receipts are polled by GetTxReceiptByPolling if it is not set
+func (rpc *RPC) ReceiptWaiter(waiter *ReceiptWaiter) *RPC {
+	rpc.waiter = waiter
+	return rpc
+}
+
+// WaitReceipt get the receipt of the transaction by the waiter set by ReceiptWaiter, the waiting times out
+// as GetTxReceiptByPolling does and the success flag is false in that case. The receipt is polled by
+// GetTxReceiptByPolling if no waiter is set.
+func (rpc *RPC) WaitReceipt(txHash string, isPrivateTx bool) (*TxReceipt, StdError, bool) {
+	if rpc.waiter == nil {
+		return rpc.GetTxReceiptByPolling(txHash, isPrivateTx)
+	}
+	return rpc.waiter.wait(rpc, txHash, isPrivateTx)
+}
+
+func (w *ReceiptWaiter) wait(rpc *RPC, txHash string, isPrivateTx bool) (receipt *TxReceipt, err StdError, success bool) {
+	txHash = chPrefix(txHash)
+	if isPrivateTx {
+		return rpc.GetTxReceiptByPolling(txHash, isPrivateTx)
+	}
+	lost, ok := w.subscribe()
+	if !ok {
+		return rpc.GetTxReceiptByPolling(txHash, isPrivateTx)
+	}
+	committed := w.add(txHash)
+	defer w.remove(txHash)
+
+	span, rpc := rpc.startSpan("WaitReceipt")
+	span.SetTag(TagTxHash, txHash)
+	defer func() {
+		finishSpan(span, err)
+	}()
+
+	// the transaction may be committed before it is waited
+	receipt, err = rpc.GetTxReceipt(txHash, isPrivateTx)
+	if err == nil || !IsRetryable(err) {
+		rpc.hrm.observePolling(1, err)
+		return receipt, err, true
+	}
+
+	timeout := rpc.firstPollTime*rpc.firstPollInterval + rpc.secondPollTime*rpc.secondPollInterval
+	timer := time.NewTimer(time.Millisecond * time.Duration(timeout))
+	defer timer.Stop()
+	ctx := rpc.getContext()
+	select {
+	case <-committed:
+		// the receipt may be saved a little later than the block event
+		return rpc.GetTxReceiptByPolling(txHash, isPrivateTx)
+	case <-lost:
+		logger.Warningf("block subscription is lost, poll receipt of %s", txHash)
+		return rpc.GetTxReceiptByPolling(txHash, isPrivateTx)
+	case <-ctx.Done():
+		err = NewRequestCanceledError(ctx.Err())
+		rpc.hrm.observePolling(1, err)
+		return nil, err, true
+	case <-timer.C:
+		err = newPollingTimeoutError()
+		rpc.hrm.observePolling(1, err)
+		return nil, err, false
+	}
+}
+
+// subscribe subscribes block events if they are not subscribed, it return the channel closed
+// when the subscription is lost, and false if block events can not be subscribed
+func (w *ReceiptWaiter) subscribe() (<-chan struct{}, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return nil, false
+	}
+	if w.lost != nil {
+		return w.lost, true
+	}
+
+	filter := NewBlockEventFilter()
+	filter.SetBlockInfo(true)
+	lost := make(chan struct{})
+	for i, node := range w.rpc.hrm.nodes {
+		if node.wsURL == "" || !node.isAvailable() {
+			continue
+		}
+		// node index of websocket client starts from 1
+		subID, err := w.wsCli.Subscribe(i+1, filter, &blockEventHandler{waiter: w, lost: lost})
+		if err != nil {
+			logger.Warningf("subscribe block events of %s error: %v", node.wsURL, err)
+			continue
+		}
+		w.nodeIndex, w.subID, w.lost = i+1, subID, lost
+		return lost, true
+	}
+	return nil, false
+}
+
+// add registers a waiter of txHash, it return the channel closed when the transaction is committed
+func (w *ReceiptWaiter) add(txHash string) <-chan struct{} {
+	txHash = strings.ToLower(txHash)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	p, ok := w.pending[txHash]
+	if !ok {
+		p = &pendingReceipt{committed: make(chan struct{})}
+		w.pending[txHash] = p
+	}
+	p.waiters++
+	return p.committed
+}
+
+func (w *ReceiptWaiter) remove(txHash string) {
+	txHash = strings.ToLower(txHash)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if p, ok := w.pending[txHash]; ok {
+		p.waiters--
+		if p.waiters == 0 {
+			delete(w.pending, txHash)
+		}
+	}
+}
+
+// commit wakes up the waiters of the transactions in the block
+func (w *ReceiptWaiter) commit(data []byte) {
+	var block struct {
+		Transactions []struct {
+			Hash string `json:"hash"`
+		} `json:"transactions"`
+	}
+	if err := json.Unmarshal(data, &block); err != nil {
+		logger.Errorf("unmarshal block event error: %v", err)
+		return
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, tx := range block.Transactions {
+		txHash := strings.ToLower(tx.Hash)
+		if p, ok := w.pending[txHash]; ok {
+			close(p.committed)
+			delete(w.pending, txHash)
+		}
+	}
+}
+
+// unsubscribed marks the subscription as lost, block events are subscribed again by the next waiter
+func (w *ReceiptWaiter) unsubscribed(lost chan struct{}) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.lost == lost {
+		close(lost)
+		w.lost, w.subID = nil, ""
+	}
+}
+
+// Close unsubscribes block events and closes the websocket connection, the waiters fall back to polling
+func (w *ReceiptWaiter) Close() StdError {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	lost, subID, nodeIndex := w.lost, w.subID, w.nodeIndex
+	w.mutex.Unlock()
+
+	if lost == nil {
+		return nil
+	}
+	w.unsubscribed(lost)
+	var errs []string
+	if err := w.wsCli.UnSubscribe(subID); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := w.wsCli.CloseConn(nodeIndex); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return NewSystemError(errors.New(strings.Join(errs, "; ")))
+	}
+	return nil
+}
+
+// blockEventHandler handles the block events of a subscription of ReceiptWaiter
+type blockEventHandler struct {
+	waiter *ReceiptWaiter
+	lost   chan struct{}
+}
+
+func (h *blockEventHandler) OnSubscribe() {}
+
+func (h *blockEventHandler) OnUnSubscribe() {}
+
+func (h *blockEventHandler) OnMessage(data []byte) {
+	h.waiter.commit(data)
+}
+
+func (h *blockEventHandler) OnClose() {
+	h.waiter.unsubscribed(h.lost)
+}
@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiptWaiter(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 2)
+	defer closeFn()
+	srv.SetTxHasher(hashLikeClient(rp))
+	waiter := rp.NewReceiptWaiter()
+	defer waiter.Close()
+	rp.ReceiptWaiter(waiter)
+
+	tx := NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1)
+	tx.txVersion = rp.txVersion
+	type result struct {
+		receipt *TxReceipt
+		err     StdError
+		success bool
+	}
+	done := make(chan result, 1)
+	go func() {
+		receipt, err, success := rp.WaitReceipt(tx.GetHash(), false)
+		done <- result{receipt, err, success}
+	}()
+	// wait until the receipt is queried once before waiting for the block event
+	assert.Eventually(t, func() bool {
+		return srv.Requests(TRANSACTION+"getTransactionReceipt") == 1
+	}, time.Second, time.Millisecond)
+	// let the query reply before the transaction is committed
+	time.Sleep(20 * time.Millisecond)
+
+	hash, err := rp.SendTxReturnHash(tx)
+	assert.Nil(t, err)
+	res := <-done
+	assert.Nil(t, res.err)
+	assert.True(t, res.success)
+	assert.Equal(t, hash, res.receipt.TxHash)
+	// the receipt is queried before waiting and after the block event only
+	assert.Equal(t, 2, srv.Requests(TRANSACTION+"getTransactionReceipt"))
+
+	// the waiter is used by the apis sending transactions
+	receipt, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 2))
+	assert.Nil(t, err)
+	assert.True(t, receipt.Valid)
+	assert.Equal(t, 3, srv.Requests(TRANSACTION+"getTransactionReceipt"))
+
+	// the waiting times out as polling does
+	start := time.Now()
+	_, err, success := rp.WaitReceipt("0x1234", false)
+	assert.False(t, success)
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	assert.Equal(t, 4, srv.Requests(TRANSACTION+"getTransactionReceipt"))
+	assert.Empty(t, waiter.pending)
+}
+
+func TestReceiptWaiter_Fallback(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetReceiptDelay(2)
+	// websocket port is not configured
+	rp.hrm.nodes[0].wsURL = ""
+	waiter := rp.NewReceiptWaiter()
+	defer waiter.Close()
+	rp.ReceiptWaiter(waiter)
+
+	receipt, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.Nil(t, err)
+	assert.True(t, receipt.Valid)
+	assert.Equal(t, 3, srv.Requests(TRANSACTION+"getTransactionReceipt"))
+	assert.Nil(t, waiter.lost)
+}
+
+func TestReceiptWaiter_Close(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	srv.SetReceiptDelay(2)
+	waiter := rp.NewReceiptWaiter()
+	rp.ReceiptWaiter(waiter)
+
+	lost, ok := waiter.subscribe()
+	assert.True(t, ok)
+	assert.Nil(t, waiter.Close())
+	select {
+	case <-lost:
+	default:
+		t.Fatal("subscription is not lost after close")
+	}
+
+	// receipt is polled after the waiter is closed
+	receipt, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.Nil(t, err)
+	assert.True(t, receipt.Valid)
+	assert.Equal(t, 3, srv.Requests(TRANSACTION+"getTransactionReceipt"))
+}
+
+func TestReceiptWaiter_Lost(t *testing.T) {
+	rp, srv, closeFn := newRPCWithServer(t, 1)
+	defer closeFn()
+	waiter := rp.NewReceiptWaiter()
+	defer waiter.Close()
+	rp.ReceiptWaiter(waiter)
+
+	lost, ok := waiter.subscribe()
+	assert.True(t, ok)
+	srv.CloseWebSockets()
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Fatal("subscription is not lost after the connection is broken")
+	}
+
+	// block events are subscribed again
+	receipt, err := rp.SendTx(NewTransaction(testFrom).Transfer("0x6201cb0448964ac597faf6fdf1f472edf2a22b89", 1))
+	assert.Nil(t, err)
+	assert.True(t, receipt.Valid)
+	waiter.mutex.Lock()
+	assert.NotNil(t, waiter.lost)
+	assert.True(t, (<-chan struct{})(waiter.lost) != lost)
+	waiter.mutex.Unlock()
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -53,11 +54,21 @@ type WsEventHandler interface {
 type connectionWrapper struct {
 	id            int
 	conn          *websocket.Conn
-	mutex         sync.Mutex // use to sync conn
+	mutex         sync.Mutex   // use to sync conn
+	hubMutex      sync.RWMutex // use to sync handler, eventHub and subscriptions
 	handler       WsEventHandler
 	subIDCh       chan SubscriptionID
 	eventHub      map[SubscriptionID]WsEventHandler
 	subscriptions []Subscription
+	// done is closed when the connection is cleared
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func (wrapper *connectionWrapper) close() {
+	wrapper.doneOnce.Do(func() {
+		close(wrapper.done)
+	})
 }
 
 // WebSocketClient control the all APIs web socket related APIs
@@ -80,16 +91,22 @@ type Subscription struct {
 	SubscriptionID SubscriptionID `json:"subId"`
 }
 
-func (wscli *WebSocketClient) getConn(nodeIndex int) (*websocket.Conn, StdError) {
-	nodeURL := wscli.hrm.nodes[nodeIndex].wsURL
-	logger.Debug("web socket url:", nodeURL)
+func newWebSocketClient(hrm *httpRequestManager) *WebSocketClient {
+	return &WebSocketClient{
+		conns:   make(map[int]*connectionWrapper, len(hrm.nodes)),
+		hrm:     hrm,
+		rwMutex: sync.RWMutex{},
+	}
+}
 
+// wsHeader return the header of websocket handshake to the node, tcert is added if sendTcert is enabled
+func (hrm *httpRequestManager) wsHeader(nodeIndex int) (http.Header, StdError) {
 	header := make(http.Header)
 	header["origin"] = []string{"haha"}
-	if wscli.hrm.sendTcert {
-		if wscli.hrm.tcm.cfca {
-			header.Add("tcert", wscli.hrm.tcm.ecert)
-			signature, err := wscli.hrm.tcm.sdkCert.Sign([]byte{})
+	if hrm.sendTcert {
+		if hrm.tcm.cfca {
+			header.Add("tcert", hrm.tcm.ecert)
+			signature, err := hrm.tcm.sdkCert.Sign([]byte{})
 			if err != nil {
 				logger.Error("signature body error", err)
 				return nil, NewSystemError(err)
@@ -97,8 +114,8 @@ func (wscli *WebSocketClient) getConn(nodeIndex int) (*websocket.Conn, StdError)
 			header.Add("signature", common.Bytes2Hex(signature))
 			header.Add("msg", common.Bytes2Hex([]byte{}))
 		} else {
-			header.Add("tcert", string(wscli.hrm.tcm.tcertPool[wscli.hrm.nodes[nodeIndex].url]))
-			signature, err := wscli.hrm.tcm.uniqueCert.Sign([]byte{})
+			header.Add("tcert", string(hrm.tcm.tcertPool[hrm.nodes[nodeIndex].url]))
+			signature, err := hrm.tcm.uniqueCert.Sign([]byte{})
 			if err != nil {
 				logger.Error("signature body error,", err)
 				return nil, NewSystemError(err)
@@ -107,6 +124,17 @@ func (wscli *WebSocketClient) getConn(nodeIndex int) (*websocket.Conn, StdError)
 			header.Add("msg", common.Bytes2Hex([]byte{}))
 		}
 	}
+	return header, nil
+}
+
+func (wscli *WebSocketClient) getConn(nodeIndex int) (*websocket.Conn, StdError) {
+	nodeURL := wscli.hrm.nodes[nodeIndex].wsURL
+	logger.Debug("web socket url:", nodeURL)
+
+	header, stdErr := wscli.hrm.wsHeader(nodeIndex)
+	if stdErr != nil {
+		return nil, stdErr
+	}
 
 	conn, resp, err := websocket.DefaultDialer.Dial(nodeURL, header)
 	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
@@ -143,10 +171,10 @@ func (wscli *WebSocketClient) listen(wrapper *connectionWrapper) {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) {
 				logger.Errorf("web socket of node%d encountered an error: %v", wrapper.id, err)
 			}
-			if _, ok := err.(*websocket.CloseError); ok {
-				wscli.clearConn(wrapper.id)
-				return
-			}
+			// the close handler is not called if the connection is broken, so handle both here
+			_ = conn.Close()
+			wscli.closeWrapper(wrapper)
+			return
 		}
 
 		jsonResponse := &JSONResponse{}
@@ -174,13 +202,18 @@ func (wscli *WebSocketClient) listen(wrapper *connectionWrapper) {
 							break
 						}
 
-						go func() {
-							// inform user of the subID
-							wrapper.subIDCh <- SubscriptionID(subID)
-							wrapper.eventHub[SubscriptionID(subID)] = wrapper.handler
-							// notify user
-							wrapper.eventHub[SubscriptionID(subID)].OnSubscribe()
-						}()
+						// register the handler before any notification is read
+						wrapper.hubMutex.Lock()
+						handler := wrapper.handler
+						wrapper.eventHub[subID] = handler
+						wrapper.hubMutex.Unlock()
+						// inform user of the subID
+						select {
+						case wrapper.subIDCh <- subID:
+						case <-wrapper.done:
+						}
+						// notify user
+						go handler.OnSubscribe()
 					}
 				}
 			} else {
@@ -191,13 +224,16 @@ func (wscli *WebSocketClient) listen(wrapper *connectionWrapper) {
 					logger.Errorf("web socket of node %d encountered an error: %v", wrapper.id, err)
 					break
 				}
+				wrapper.hubMutex.RLock()
+				handler, ok := wrapper.eventHub[notification.Subscription]
+				wrapper.hubMutex.RUnlock()
 				// if the callback has been removed, try to unsubscribe again
-				if _, ok := wrapper.eventHub[notification.Subscription]; !ok {
+				if !ok {
 					//nolint
 					go wscli.UnSubscribe(notification.Subscription)
 				} else {
 					// notify user
-					go wrapper.eventHub[notification.Subscription].OnMessage(notification.Data)
+					go handler.OnMessage(notification.Data)
 				}
 			}
 		default:
@@ -211,27 +247,53 @@ func (wscli *WebSocketClient) checkIndex(index int) bool {
 	return 0 <= index && index <= len(wscli.hrm.nodes)
 }
 
+// clearConn clears the connection of node, the lock of conns should be held
 func (wscli *WebSocketClient) clearConn(nodeIndex int) {
 	if wscli.conns[nodeIndex] != nil {
 		// clear subIDs
+		wscli.conns[nodeIndex].hubMutex.Lock()
 		wscli.conns[nodeIndex].subscriptions = make([]Subscription, 0)
-		// close channel
-		close(wscli.conns[nodeIndex].subIDCh)
+		wscli.conns[nodeIndex].hubMutex.Unlock()
+		// unblock the subscriber
+		wscli.conns[nodeIndex].close()
 		// clear the connection
 		wscli.conns[nodeIndex] = nil
 	}
 }
 
-func (wscli *WebSocketClient) getCloseHandler(wrapper *connectionWrapper) func(code int, text string) error {
-	return func(code int, text string) error {
-		// notify user
-		for _, h := range wrapper.eventHub {
-			h.OnClose()
-		}
-
-		// clear the connection and callback
+// clearWrapper clears the connection of wrapper unless it is replaced by a new one
+func (wscli *WebSocketClient) clearWrapper(wrapper *connectionWrapper) {
+	// the subscriber waiting for subID holds the lock, unblock it first
+	wrapper.close()
+	wscli.rwMutex.Lock()
+	defer wscli.rwMutex.Unlock()
+	if wscli.conns[wrapper.id] == wrapper {
 		wscli.clearConn(wrapper.id)
+	}
+}
+
+// closeWrapper notifies the handlers of wrapper that the connection is closed, then clears the connection
+func (wscli *WebSocketClient) closeWrapper(wrapper *connectionWrapper) {
+	// notify user
+	wrapper.hubMutex.RLock()
+	handlers := make([]WsEventHandler, 0, len(wrapper.eventHub))
+	for _, h := range wrapper.eventHub {
+		handlers = append(handlers, h)
+	}
+	wrapper.hubMutex.RUnlock()
+	for _, h := range handlers {
+		h.OnClose()
+	}
+
+	// clear the connection and callback
+	wscli.clearWrapper(wrapper)
+}
 
+// getCloseHandler return the handler of close message, the connection is closed by listen
+// when the close error is read
+func (wscli *WebSocketClient) getCloseHandler(wrapper *connectionWrapper) func(code int, text string) error {
+	return func(code int, text string) error {
+		logger.Debugf("web socket of node%d is closed: %d %s", wrapper.id, code, text)
 		return nil
 	}
 }
@@ -257,17 +319,20 @@ func (wscli *WebSocketClient) Subscribe(nodeIndex int, filter EventFilter, event
 	return wscli.subscribe(nodeIndex, eventHandler, method, params, filter.GetEventType())
 }
 
-func (wscli *WebSocketClient) subscribe(nodeIndex int, eventHandler WsEventHandler, method string, params []interface{}, eventType EventType) (SubscriptionID, StdError) {
+func (wscli *WebSocketClient) subscribe(nodeIndex int, eventHandler WsEventHandler, method string, params []interface{}, eventType EventType) (subID SubscriptionID, stdErr StdError) {
 	if !wscli.checkIndex(nodeIndex) {
 		return "", NewSystemError(fmt.Errorf("node index out of range, suppose to be in [0, %d)", len(wscli.hrm.nodes)))
 	}
 
-	var (
-		stdErr StdError
-		conn   *websocket.Conn
-	)
+	var conn *websocket.Conn
 	nodeIndex = nodeIndex - 1
 
+	span, _ := wscli.hrm.startSpan(context.Background(), method)
+	span.SetTag(TagMethod, method)
+	defer func() {
+		finishSpan(span, stdErr)
+	}()
+
 	// lock conns
 	wscli.rwMutex.Lock()
 	defer wscli.rwMutex.Unlock()
@@ -280,8 +345,10 @@ func (wscli *WebSocketClient) subscribe(nodeIndex int, eventHandler WsEventHandl
 			id:            nodeIndex,
 			subscriptions: make([]Subscription, 0),
 			eventHub:      make(map[SubscriptionID]WsEventHandler),
+			done:          make(chan struct{}),
 		}
 		if wscli.conns[nodeIndex].conn, stdErr = wscli.getConn(nodeIndex); stdErr != nil {
+			wscli.conns[nodeIndex] = nil
 			return "", stdErr
 		}
 		// init when connect success
@@ -293,6 +360,7 @@ func (wscli *WebSocketClient) subscribe(nodeIndex int, eventHandler WsEventHandl
 	}
 
 	conn = wscli.conns[nodeIndex].conn
+	span.SetTag(TagNodeURL, wscli.hrm.nodes[nodeIndex].wsURL)
 
 	jsonReq := JSONRequest{
 		Method:    method,
@@ -310,7 +378,9 @@ func (wscli *WebSocketClient) subscribe(nodeIndex int, eventHandler WsEventHandl
 	logger.Debugf("[WEB SOCKET REQUEST]: %s", string(req))
 
 	// before request
+	wscli.conns[nodeIndex].hubMutex.Lock()
 	wscli.conns[nodeIndex].handler = eventHandler
+	wscli.conns[nodeIndex].hubMutex.Unlock()
 
 	// lock conn
 	wscli.conns[nodeIndex].mutex.Lock()
@@ -321,14 +391,17 @@ func (wscli *WebSocketClient) subscribe(nodeIndex int, eventHandler WsEventHandl
 	}
 	wscli.conns[nodeIndex].mutex.Unlock()
 
-	subID, ok := <-wscli.conns[nodeIndex].subIDCh
-	if !ok {
+	wrapper := wscli.conns[nodeIndex]
+	select {
+	case subID = <-wrapper.subIDCh:
+	case <-wrapper.done:
 		return "", NewSystemError(fmt.Errorf("the connection of the node%d is closed, please try again", nodeIndex))
 	}
 
 	// store eventType=>subID
-	wscli.conns[nodeIndex].subscriptions =
-		append(wscli.conns[nodeIndex].subscriptions, Subscription{Event: eventType, SubscriptionID: subID})
+	wrapper.hubMutex.Lock()
+	wrapper.subscriptions = append(wrapper.subscriptions, Subscription{Event: eventType, SubscriptionID: subID})
+	wrapper.hubMutex.Unlock()
 
 	return subID, nil
 }
@@ -378,38 +451,43 @@ func (wscli *WebSocketClient) UnSubscribe(id SubscriptionID) StdError {
 		return NewSystemError(err)
 	}
 
-	for k := range wscli.conns {
-		for k1 := range wscli.conns[k].eventHub {
-			if k1 == id {
-				logger.Debugf("[WEB SOCKET REQUEST]: %s", string(req))
+	wscli.rwMutex.RLock()
+	defer wscli.rwMutex.RUnlock()
 
-				wscli.rwMutex.RLock()
-				defer wscli.rwMutex.RUnlock()
+	for _, wrapper := range wscli.conns {
+		if wrapper == nil {
+			continue
+		}
+		wrapper.hubMutex.RLock()
+		handler, ok := wrapper.eventHub[id]
+		wrapper.hubMutex.RUnlock()
+		if !ok {
+			continue
+		}
+		logger.Debugf("[WEB SOCKET REQUEST]: %s", string(req))
 
-				// try to unsubscribe
-				if wscli.conns[k] != nil {
-					wscli.conns[k].mutex.Lock()
-					if err := wscli.conns[k].conn.WriteMessage(websocket.TextMessage, req); err != nil {
-						wscli.conns[k].mutex.Unlock()
-						return NewSystemError(err)
-					}
-					wscli.conns[k].mutex.Unlock()
+		// try to unsubscribe
+		wrapper.mutex.Lock()
+		if err := wrapper.conn.WriteMessage(websocket.TextMessage, req); err != nil {
+			wrapper.mutex.Unlock()
+			return NewSystemError(err)
+		}
+		wrapper.mutex.Unlock()
 
-					// notify user
-					go wscli.conns[k].eventHub[id].OnUnSubscribe()
-					// clear callback
-					delete(wscli.conns[k].eventHub, id)
-					// clear subID
-					for i := range wscli.conns[k].subscriptions {
-						if wscli.conns[k].subscriptions[i].SubscriptionID == id {
-							wscli.conns[k].subscriptions = append(wscli.conns[k].subscriptions[:i], wscli.conns[k].subscriptions[i+1:]...)
-							break
-						}
-					}
-				}
-				return nil
+		// notify user
+		go handler.OnUnSubscribe()
+		wrapper.hubMutex.Lock()
+		// clear callback
+		delete(wrapper.eventHub, id)
+		// clear subID
+		for i := range wrapper.subscriptions {
+			if wrapper.subscriptions[i].SubscriptionID == id {
+				wrapper.subscriptions = append(wrapper.subscriptions[:i], wrapper.subscriptions[i+1:]...)
+				break
 			}
 		}
+		wrapper.hubMutex.Unlock()
+		return nil
 	}
 
 	return nil
@@ -431,7 +509,11 @@ func (wscli *WebSocketClient) GetAllSubscription(nodeIndex int) ([]Subscription,
 		return make([]Subscription, 0), nil
 	}
 
-	return wscli.conns[nodeIndex].subscriptions, nil
+	wscli.conns[nodeIndex].hubMutex.RLock()
+	defer wscli.conns[nodeIndex].hubMutex.RUnlock()
+	subscriptions := make([]Subscription, len(wscli.conns[nodeIndex].subscriptions))
+	copy(subscriptions, wscli.conns[nodeIndex].subscriptions)
+	return subscriptions, nil
 }
 
 func (wscli *WebSocketClient) pingHandler(appData string) error {
@@ -3,6 +3,7 @@ package java
 import (
 	"fmt"
 	"github.com/coreos/etcd/pkg/testutil"
+	"github.com/hyperchain/gosdk/account"
 	"github.com/hyperchain/gosdk/common"
 	"github.com/hyperchain/gosdk/rpc"
 	"github.com/stretchr/testify/assert"
@@ -17,10 +18,10 @@ var (
 	guomiPri = "6153af264daa4763490f2a51c9d13417ef9f579229be2141574eb339ee9b9d2a"
 	pri      = new(gm.SM2PrivateKey).FromBytes(common.FromHex(guomiPri))
 
-	guomiKey = &gm.SM2PrivateKey{
+	guomiKey = &account.SM2Key{SM2PrivateKey: &gm.SM2PrivateKey{
 		K:         pri.K,
 		PublicKey: pri.CalculatePublicKey().PublicKey,
-	}
+	}}
 	contractAddress = "0x31cf62472b1856d94553d2fe78f3bb067afb0714"
 )
 